@@ -0,0 +1,116 @@
+package tripod
+
+// Represents an occurrence of a stored key in a text: text[Start:End] is the
+// key.
+type Match struct {
+	Start int
+	End   int
+}
+
+// Represents an Aho-Corasick automaton compiled over the nodes of a
+// PrefixStoreByteTrie, which finds all the occurrences of all the stored keys
+// in a text in a single pass over it.
+// The automaton reads the nodes of the trie it was compiled from, hence the
+// trie must not be modified while the automaton is in use, and the
+// automaton must be compiled again to reflect any later change.
+type AhoCorasickByteTrie struct {
+	root *PrefixStoreByteTrie
+
+	// The failure links and output links are kept alongside the trie
+	// instead of in its nodes, so that tries never compiled into an
+	// automaton do not pay for them.
+	links map[*PrefixStoreByteTrie]ahoCorasickLinks
+}
+
+// Links of a node of the automaton.
+type ahoCorasickLinks struct {
+	// Node for the longest proper suffix of the path to this node that is
+	// also a path in the trie; followed when the next byte has no child.
+	fail *PrefixStoreByteTrie
+
+	// Node for the longest proper suffix of the path to this node that is a
+	// stored key, or nil; followed to report keys ending at the same
+	// position.
+	output *PrefixStoreByteTrie
+
+	// Length of the path to this node.
+	depth int
+}
+
+// Compiles and returns an AhoCorasickByteTrie over the keys of t by adding
+// failure links to the nodes of t in a breadth first traversal.
+func CompileAhoCorasick(t *PrefixStoreByteTrie) *AhoCorasickByteTrie {
+	a := &AhoCorasickByteTrie{
+		root:  t,
+		links: map[*PrefixStoreByteTrie]ahoCorasickLinks{t: {fail: t}},
+	}
+
+	queue := []*PrefixStoreByteTrie{t}
+	for len(queue) > 0 {
+		parent := queue[0]
+		queue = queue[1:]
+		parentLinks := a.links[parent]
+
+		for ch, child := range parent.children {
+			fail := t
+			if parent != t {
+				// Falls back along the failure links of the parent until
+				// a node can be extended by the same byte.
+				f := parentLinks.fail
+				for f != t && f.children[ch] == nil {
+					f = a.links[f].fail
+				}
+				if next := f.children[ch]; next != nil {
+					fail = next
+				}
+			}
+
+			var output *PrefixStoreByteTrie
+			if fail.isLast {
+				output = fail
+			} else {
+				output = a.links[fail].output
+			}
+
+			a.links[child] = ahoCorasickLinks{
+				fail:   fail,
+				output: output,
+				depth:  parentLinks.depth + 1,
+			}
+			queue = append(queue, child)
+		}
+	}
+	return a
+}
+
+// Scans text in a single pass and calls fn with every occurrence of every
+// stored key, in the order of their end offsets; the keys ending at the same
+// offset are reported longest first. Overlapping occurrences are all
+// reported. The scan stops as soon as fn returns false.
+func (a *AhoCorasickByteTrie) FindAll(text []byte, fn func(m Match) bool) {
+	current_node := a.root
+	for i, b := range text {
+		for {
+			if child := current_node.children[int(b)]; child != nil {
+				current_node = child
+				break
+			}
+			if current_node == a.root {
+				break
+			}
+			current_node = a.links[current_node].fail
+		}
+
+		end := i + 1
+		if current_node.isLast {
+			if !fn(Match{Start: end - a.links[current_node].depth, End: end}) {
+				return
+			}
+		}
+		for output := a.links[current_node].output; output != nil; output = a.links[output].output {
+			if !fn(Match{Start: end - a.links[output].depth, End: end}) {
+				return
+			}
+		}
+	}
+}
@@ -3,6 +3,7 @@ package benchmark_tripod
 import (
 	"github.com/arpitbbhayani/tripod"
 	"math/rand"
+	"sort"
 	"testing"
 )
 
@@ -93,3 +94,48 @@ func BenchmarkByteTriePrefixSearch16_200(b *testing.B)  { benchmarkPrefixSearch(
 func BenchmarkByteTriePrefixSearch32_200(b *testing.B)  { benchmarkPrefixSearch(b, 32, 200) }
 func BenchmarkByteTriePrefixSearch64_200(b *testing.B)  { benchmarkPrefixSearch(b, 64, 200) }
 func BenchmarkByteTriePrefixSearch128_200(b *testing.B) { benchmarkPrefixSearch(b, 128, 200) }
+
+// Returns count sorted keys of given size, sharing all but their last 4
+// bytes with one of a hundred stems as in a real world dictionary.
+func getSortedByteSlices(size int, count int) [][]byte {
+	stems := make([][]byte, 100)
+	for i := range stems {
+		stems[i] = getRandomByteSlice(size - 4)
+	}
+	keys := make([][]byte, count)
+	for i := 0; i < count; i++ {
+		keys[i] = append(append([]byte{}, stems[rand.Intn(len(stems))]...), getRandomByteSlice(4)...)
+	}
+	sort.Slice(keys, func(i, j int) bool { return string(keys[i]) < string(keys[j]) })
+	return keys
+}
+
+func benchmarkPutSorted(b *testing.B, size int, count int) {
+	keys := getSortedByteSlices(size, count)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		tr := tripod.CreatePrefixStoreByteTrie(128)
+		for _, key := range keys {
+			tr.Put(key)
+		}
+	}
+}
+
+func BenchmarkByteTriePutSorted32_10000(b *testing.B) { benchmarkPutSorted(b, 32, 10000) }
+
+func benchmarkBuildFromSorted(b *testing.B, size int, count int) {
+	keys := getSortedByteSlices(size, count)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		i := 0
+		tripod.BuildPrefixStoreByteTrieFromSorted(128, func() ([]byte, bool) {
+			if i == len(keys) {
+				return nil, false
+			}
+			i++
+			return keys[i-1], true
+		})
+	}
+}
+
+func BenchmarkByteTrieBuildFromSorted32_10000(b *testing.B) { benchmarkBuildFromSorted(b, 32, 10000) }
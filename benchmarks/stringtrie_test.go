@@ -0,0 +1,67 @@
+package benchmark_tripod
+
+import (
+	"github.com/arpitbbhayani/tripod"
+	"testing"
+)
+
+func benchmarkStringPut(b *testing.B, size int) {
+	tr := tripod.CreatePrefixStoreString(128)
+	x := string(getRandomByteSlice(size))
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		tr.Put(x)
+	}
+}
+
+func BenchmarkStringTriePut8(b *testing.B)   { benchmarkStringPut(b, 8) }
+func BenchmarkStringTriePut32(b *testing.B)  { benchmarkStringPut(b, 32) }
+func BenchmarkStringTriePut128(b *testing.B) { benchmarkStringPut(b, 128) }
+
+func benchmarkStringExists(b *testing.B, size int) {
+	tr := tripod.CreatePrefixStoreString(128)
+	x := string(getRandomByteSlice(size))
+	tr.Put(x)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		tr.Exists(x)
+	}
+}
+
+func BenchmarkStringTrieExists8(b *testing.B)   { benchmarkStringExists(b, 8) }
+func BenchmarkStringTrieExists32(b *testing.B)  { benchmarkStringExists(b, 32) }
+func BenchmarkStringTrieExists128(b *testing.B) { benchmarkStringExists(b, 128) }
+
+// The baseline PrefixStoreString is meant to beat: a PrefixStoreByteTrie
+// converting the string key on every call.
+func benchmarkStringExistsOnByteTrie(b *testing.B, size int) {
+	tr := tripod.CreatePrefixStoreByteTrie(128)
+	x := string(getRandomByteSlice(size))
+	tr.Put([]byte(x))
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		tr.Exists([]byte(x))
+	}
+}
+
+func BenchmarkStringOnByteTrieExists8(b *testing.B)   { benchmarkStringExistsOnByteTrie(b, 8) }
+func BenchmarkStringOnByteTrieExists32(b *testing.B)  { benchmarkStringExistsOnByteTrie(b, 32) }
+func BenchmarkStringOnByteTrieExists128(b *testing.B) { benchmarkStringExistsOnByteTrie(b, 128) }
+
+func benchmarkStringPrefixSearch(b *testing.B, size int, count int) {
+	tr := tripod.CreatePrefixStoreString(128)
+	for i := 0; i < count; i++ {
+		x := getRandomByteSlice(size)
+		x[0] = 'a'
+		tr.Put(string(x))
+	}
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		tr.PrefixSearch("a")
+	}
+}
+
+func BenchmarkStringTriePrefixSearch32_50(b *testing.B)   { benchmarkStringPrefixSearch(b, 32, 50) }
+func BenchmarkStringTriePrefixSearch128_50(b *testing.B)  { benchmarkStringPrefixSearch(b, 128, 50) }
+func BenchmarkStringTriePrefixSearch32_200(b *testing.B)  { benchmarkStringPrefixSearch(b, 32, 200) }
+func BenchmarkStringTriePrefixSearch128_200(b *testing.B) { benchmarkStringPrefixSearch(b, 128, 200) }
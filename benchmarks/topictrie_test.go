@@ -0,0 +1,24 @@
+package benchmark_tripod
+
+import (
+	"fmt"
+	"github.com/arpitbbhayani/tripod"
+	"testing"
+)
+
+func benchmarkTopicMatch(b *testing.B, count int) {
+	tr := tripod.CreateTopicTrie()
+	for i := 0; i < count; i++ {
+		tr.Subscribe(fmt.Sprintf("devices/%d/telemetry/%d", i%1000, i))
+		tr.Subscribe(fmt.Sprintf("devices/%d/+/%d", i%1000, i))
+	}
+	tr.Subscribe("devices/+/telemetry/#")
+	topic := "devices/42/telemetry/42"
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		tr.Match(topic)
+	}
+}
+
+func BenchmarkTopicTrieMatch_10000(b *testing.B)  { benchmarkTopicMatch(b, 10000) }
+func BenchmarkTopicTrieMatch_100000(b *testing.B) { benchmarkTopicMatch(b, 100000) }
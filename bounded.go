@@ -0,0 +1,273 @@
+package tripod
+
+import (
+	"container/heap"
+	"container/list"
+	"sync"
+	"unsafe"
+)
+
+// Represents which key a BoundedPrefixStore evicts once it is full.
+type EvictionPolicy int
+
+const (
+	// Evicts the least recently used key.
+	EvictLRU EvictionPolicy = iota
+
+	// Evicts the least frequently used key, the least recently used one
+	// among equally used keys.
+	EvictLFU
+)
+
+// Options of a BoundedPrefixStore. A zero limit means no limit.
+type BoundedOptions struct {
+	// Maximum number of keys held by the store.
+	MaxKeys int
+
+	// Maximum number of bytes of memory held by the store, as estimated from
+	// the number of trie nodes and of keys.
+	MaxBytes int64
+
+	Policy EvictionPolicy
+}
+
+// Rough number of bytes held by the bookkeeping of a key, besides the bytes
+// of the key: its entry, its slot in the index and in the eviction heap.
+var boundedEntryBytes = int64(unsafe.Sizeof(boundedEntry{})) +
+	int64(unsafe.Sizeof("")+2*unsafe.Sizeof((*boundedEntry)(nil))+mapSlotCtrlBytes)
+
+// The usage of a key, along with its index in the eviction heap.
+type boundedEntry struct {
+	key      string
+	uses     uint64
+	lastUsed uint64
+	index    int
+}
+
+// A min-heap of key usages, the next key to evict being at the top.
+type boundedHeap struct {
+	entries []*boundedEntry
+	policy  EvictionPolicy
+}
+
+func (h *boundedHeap) Len() int { return len(h.entries) }
+func (h *boundedHeap) Less(i, j int) bool {
+	a, b := h.entries[i], h.entries[j]
+	if h.policy == EvictLFU && a.uses != b.uses {
+		return a.uses < b.uses
+	}
+	return a.lastUsed < b.lastUsed
+}
+func (h *boundedHeap) Swap(i, j int) {
+	h.entries[i], h.entries[j] = h.entries[j], h.entries[i]
+	h.entries[i].index = i
+	h.entries[j].index = j
+}
+func (h *boundedHeap) Push(x interface{}) {
+	entry := x.(*boundedEntry)
+	entry.index = len(h.entries)
+	h.entries = append(h.entries, entry)
+}
+func (h *boundedHeap) Pop() interface{} {
+	old := h.entries
+	entry := old[len(old)-1]
+	old[len(old)-1] = nil
+	h.entries = old[:len(old)-1]
+	return entry
+}
+
+// Represents a BytePrefixStore holding at most a given number of keys, or of
+// estimated bytes. Putting a key into a full store evicts the least recently,
+// or the least frequently, used keys, and prunes their nodes, until it fits.
+// A key is used when it is put, or found by Exists; keys returned by
+// PrefixSearch are not, so that short prefixes do not keep large parts of the
+// store alive.
+// A BoundedPrefixStore is safe for concurrent use.
+type BoundedPrefixStore struct {
+	mu   sync.Mutex
+	trie *PrefixStoreByteTrie
+	opts BoundedOptions
+
+	entries   map[string]*boundedEntry
+	queue     boundedHeap
+	uses      uint64
+	bytes     int64
+	evictions int
+}
+
+// Creates and returns reference to a new instance of BoundedPrefixStore.
+// maxKeySizeInBytes is the maximum size of the key ([]byte) that should be
+// allowed to be added to the store. When tried to put key of length more than
+// maxKeySizeInBytes, the method will return the error.
+func CreateBoundedPrefixStore(maxKeySizeInBytes int, opts BoundedOptions) *BoundedPrefixStore {
+	return &BoundedPrefixStore{
+		trie:    CreatePrefixStoreByteTrie(maxKeySizeInBytes),
+		opts:    opts,
+		entries: make(map[string]*boundedEntry),
+		queue:   boundedHeap{policy: opts.Policy},
+		bytes:   byteTrieNodeBytes,
+	}
+}
+
+// Adds the key to the store, evicting other keys if the store is then over
+// its limits, and returns if key was succesfully added and any error
+// encountered. The key just put is never evicted, hence the store can be
+// over its byte limit while holding a single key.
+// A non nil error is returned if len(key) > maxKeySizeInBytes
+func (s *BoundedPrefixStore) Put(key []byte) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if entry := s.entries[string(key)]; entry != nil {
+		s.use(entry)
+		return false, nil
+	}
+
+	created := int64(len(key) - s.pathLength(key))
+	newlyAdded, err := s.trie.Put(key)
+	if err != nil || !newlyAdded {
+		return newlyAdded, err
+	}
+
+	entry := &boundedEntry{key: string(key)}
+	s.entries[entry.key] = entry
+	heap.Push(&s.queue, entry)
+	s.use(entry)
+	s.bytes += created*byteTrieNodeBytes + boundedEntryBytes + int64(len(key))
+
+	for s.full() && len(s.queue.entries) > 1 {
+		victim := s.queue.entries[0]
+		if victim == entry {
+			// The top is the key just put only when it is the least used
+			// under LFU; evict the next least used key instead.
+			victim = s.queue.entries[1]
+			if len(s.queue.entries) > 2 && s.queue.Less(2, 1) {
+				victim = s.queue.entries[2]
+			}
+		}
+		s.remove(victim)
+		s.evictions++
+	}
+	return true, nil
+}
+
+// Checks and returns if given key is present in the store, counting the key
+// as used if so.
+func (s *BoundedPrefixStore) Exists(key []byte) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.entries[string(key)]
+	if entry == nil {
+		return false
+	}
+	s.use(entry)
+	return true
+}
+
+// Does the prefix search and returns a reference to list (*list.List)
+// containing the keys present in the store for the given prefix. Each element
+// of the list is []byte.
+func (s *BoundedPrefixStore) PrefixSearch(prefix []byte) *list.List {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.trie.PrefixSearch(prefix)
+}
+
+// Removes the key from the store and returns if the key was present.
+func (s *BoundedPrefixStore) Delete(key []byte) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.entries[string(key)]
+	if entry == nil {
+		return false
+	}
+	s.remove(entry)
+	return true
+}
+
+// Returns the number of keys present in the store.
+func (s *BoundedPrefixStore) Count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.trie.Count()
+}
+
+// Returns the estimated number of bytes held by the store, as compared to
+// its byte limit.
+func (s *BoundedPrefixStore) EstimatedBytes() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.bytes
+}
+
+// Returns the number of keys evicted so far.
+func (s *BoundedPrefixStore) Evictions() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.evictions
+}
+
+// Counts entry as used now. The caller must hold the lock.
+func (s *BoundedPrefixStore) use(entry *boundedEntry) {
+	s.uses++
+	entry.uses++
+	entry.lastUsed = s.uses
+	heap.Fix(&s.queue, entry.index)
+}
+
+// Checks and returns if the store is over any of its limits.
+func (s *BoundedPrefixStore) full() bool {
+	return (s.opts.MaxKeys > 0 && len(s.entries) > s.opts.MaxKeys) ||
+		(s.opts.MaxBytes > 0 && s.bytes > s.opts.MaxBytes)
+}
+
+// Removes the key of entry from the store, pruning its nodes, and accounts
+// for the bytes freed. The caller must hold the lock.
+func (s *BoundedPrefixStore) remove(entry *boundedEntry) {
+	key := []byte(entry.key)
+	pruned := int64(s.prunedLength(key))
+	s.trie.Delete(key)
+
+	heap.Remove(&s.queue, entry.index)
+	delete(s.entries, entry.key)
+	s.bytes -= pruned*byteTrieNodeBytes + boundedEntryBytes + int64(len(key))
+}
+
+// Returns the number of bytes of key having a node in the trie, i.e. the
+// length of the longest prefix of key that is a path of the trie.
+func (s *BoundedPrefixStore) pathLength(key []byte) int {
+	current_node := s.trie
+	for i, b := range key {
+		current_node = current_node.children[int(b)]
+		if current_node == nil {
+			return i
+		}
+	}
+	return len(key)
+}
+
+// Returns the number of nodes pruned when deleting key, which must be
+// present: the nodes below the last node on its path that is the root, holds
+// another key, or has other children.
+func (s *BoundedPrefixStore) prunedLength(key []byte) int {
+	kept := 0
+	current_node := s.trie
+	for i, b := range key {
+		if current_node.isLast || len(current_node.children) > 1 {
+			kept = i
+		}
+		current_node = current_node.children[int(b)]
+	}
+	if len(current_node.children) > 0 {
+		return 0
+	}
+	return len(key) - kept
+}
+
+var _ BytePrefixStore = (*BoundedPrefixStore)(nil)
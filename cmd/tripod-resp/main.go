@@ -0,0 +1,97 @@
+// Command tripod-resp serves named prefix stores over the Redis protocol, so
+// that any Redis client can use them.
+//
+// Usage:
+//
+//	tripod-resp [-addr :6380] -store name=store.trie [-store name ...]
+//
+// A store given as name=path is read from a file written by 'tripod build',
+// while a store given only by name starts empty. See package resp for the
+// commands served.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/arpitbbhayani/tripod"
+	"github.com/arpitbbhayani/tripod/resp"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+)
+
+// Collects the repeated -store flags.
+type storeFlags []string
+
+func (f *storeFlags) String() string     { return strings.Join(*f, ",") }
+func (f *storeFlags) Set(v string) error { *f = append(*f, v); return nil }
+
+func main() {
+	var storeSpecs storeFlags
+	flag.Var(&storeSpecs, "store", "store to serve as name=path, or name for an empty store (repeatable)")
+	addr := flag.String("addr", ":6380", "address to listen on")
+	maxKeySize := flag.Int("max", 128, "maximum size of a key in runes for empty stores")
+	flag.Parse()
+
+	if len(storeSpecs) == 0 {
+		log.Fatal("tripod-resp: at least one -store is required")
+	}
+
+	server := resp.CreateServer()
+	for _, spec := range storeSpecs {
+		name, path, _ := strings.Cut(spec, "=")
+		if name == "" {
+			log.Fatalf("tripod-resp: invalid -store %q", spec)
+		}
+		if err := addStore(server, name, path, *maxKeySize); err != nil {
+			log.Fatalf("tripod-resp: store %q: %v", name, err)
+		}
+	}
+
+	l, err := net.Listen("tcp", *addr)
+	if err != nil {
+		log.Fatalf("tripod-resp: %v", err)
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		log.Printf("tripod-resp: shutting down")
+		server.Close()
+	}()
+
+	log.Printf("tripod-resp: serving %d stores on %s", len(storeSpecs), l.Addr())
+	if err := server.Serve(l); err != resp.ErrServerClosed {
+		log.Fatalf("tripod-resp: %v", err)
+	}
+}
+
+// Reads the store file at path, whichever kind of store it holds, and adds
+// it to the server. An empty path adds an empty PrefixStoreRuneTrie.
+func addStore(server *resp.Server, name, path string, maxKeySize int) error {
+	if path == "" {
+		server.AddRuneStore(name, tripod.CreatePrefixStoreRuneTrie(maxKeySize))
+		return nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	bytes, runes, err := tripod.ReadPrefixStore(f)
+	if err != nil {
+		return fmt.Errorf("reading %s: %v", path, err)
+	}
+	if bytes != nil {
+		server.AddByteStore(name, bytes)
+	} else {
+		server.AddRuneStore(name, runes)
+	}
+	return nil
+}
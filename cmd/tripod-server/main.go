@@ -0,0 +1,132 @@
+// Command tripod-server serves autocompletion over HTTP from one or more
+// named prefix stores.
+//
+// Usage:
+//
+//	tripod-server [-addr :8080] -store name=store.trie [-store name ...]
+//
+// A store given as name=path is read from a file written by 'tripod build',
+// while a store given only by name starts empty. The server exposes
+//
+//	GET  /v1/{store}/complete?prefix=..&limit=..
+//	GET  /v1/{store}/exists?key=..
+//	POST /v1/{store}/put       {"key": ".."}
+//	GET  /debug/vars           request metrics under "tripod"
+//
+// and shuts down gracefully on SIGINT or SIGTERM.
+package main
+
+import (
+	"context"
+	"expvar"
+	"flag"
+	"fmt"
+	"github.com/arpitbbhayani/tripod"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Collects the repeated -store flags.
+type storeFlags []string
+
+func (f *storeFlags) String() string     { return strings.Join(*f, ",") }
+func (f *storeFlags) Set(v string) error { *f = append(*f, v); return nil }
+
+func main() {
+	var storeSpecs storeFlags
+	flag.Var(&storeSpecs, "store", "store to serve as name=path, or name for an empty store (repeatable)")
+	addr := flag.String("addr", ":8080", "address to listen on")
+	maxKeySize := flag.Int("max", 128, "maximum size of a key in runes for empty stores")
+	defaultLimit := flag.Int("default-limit", 10, "completions returned when the request has no limit")
+	maxLimit := flag.Int("max-limit", 1000, "maximum completions a request can ask for")
+	maxBodyBytes := flag.Int64("max-body", 1<<20, "maximum size of a request body in bytes")
+	readTimeout := flag.Duration("read-timeout", 5*time.Second, "timeout for reading a request")
+	writeTimeout := flag.Duration("write-timeout", 10*time.Second, "timeout for writing a response")
+	shutdownTimeout := flag.Duration("shutdown-timeout", 10*time.Second, "time allowed for in-flight requests on shutdown")
+	flag.Parse()
+
+	if len(storeSpecs) == 0 {
+		log.Fatal("tripod-server: at least one -store is required")
+	}
+
+	stores := make(map[string]*store)
+	for _, spec := range storeSpecs {
+		name, path, _ := strings.Cut(spec, "=")
+		if name == "" {
+			log.Fatalf("tripod-server: invalid -store %q", spec)
+		}
+		if _, ok := stores[name]; ok {
+			log.Fatalf("tripod-server: duplicate store %q", name)
+		}
+		s, err := openStore(path, *maxKeySize)
+		if err != nil {
+			log.Fatalf("tripod-server: store %q: %v", name, err)
+		}
+		stores[name] = s
+	}
+
+	vars := new(expvar.Map).Init()
+	expvar.Publish("tripod", vars)
+	srv := &server{
+		stores: stores,
+		limits: limits{
+			defaultLimit: *defaultLimit,
+			maxLimit:     *maxLimit,
+			maxBodyBytes: *maxBodyBytes,
+		},
+		metrics: newMetrics(vars),
+	}
+	httpServer := &http.Server{
+		Addr:         *addr,
+		Handler:      srv.handler(),
+		ReadTimeout:  *readTimeout,
+		WriteTimeout: *writeTimeout,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errs := make(chan error, 1)
+	go func() {
+		log.Printf("tripod-server: serving %d stores on %s", len(stores), *addr)
+		errs <- httpServer.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		log.Fatalf("tripod-server: %v", err)
+	case <-ctx.Done():
+	}
+
+	log.Printf("tripod-server: shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), *shutdownTimeout)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		log.Fatalf("tripod-server: shutdown: %v", err)
+	}
+}
+
+// Reads the store file at path, whichever kind of store it holds. An empty
+// path returns an empty PrefixStoreRuneTrie.
+func openStore(path string, maxKeySize int) (*store, error) {
+	if path == "" {
+		return &store{runes: tripod.CreatePrefixStoreRuneTrie(maxKeySize)}, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	bytes, runes, err := tripod.ReadPrefixStore(f)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %v", path, err)
+	}
+	return &store{bytes: bytes, runes: runes}, nil
+}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"github.com/arpitbbhayani/tripod"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Limits applied to the requests served.
+type limits struct {
+	// Number of completions returned when the request does not ask for a
+	// limit.
+	defaultLimit int
+
+	// Maximum number of completions a request can ask for.
+	maxLimit int
+
+	// Maximum size of a request body in bytes.
+	maxBodyBytes int64
+}
+
+// A named prefix store of either kind, safe for concurrent use.
+type store struct {
+	mu    sync.RWMutex
+	bytes *tripod.PrefixStoreByteTrie
+	runes *tripod.PrefixStoreRuneTrie
+}
+
+func (s *store) put(key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.bytes != nil {
+		return s.bytes.Put([]byte(key))
+	}
+	return s.runes.Put([]rune(key))
+}
+
+func (s *store) exists(key string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.bytes != nil {
+		return s.bytes.Exists([]byte(key))
+	}
+	return s.runes.Exists([]rune(key))
+}
+
+// Returns the first limit keys having the prefix in ascending order, and if
+// there are more. The keys are walked in order from the prefix and the walk
+// stops past the limit, hence the cost does not depend on the number of keys
+// having the prefix.
+func (s *store) complete(prefix string, limit int) ([]string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var keys []string
+	truncated := false
+	collect := func(key string) bool {
+		if !strings.HasPrefix(key, prefix) {
+			return false
+		}
+		if len(keys) == limit {
+			truncated = true
+			return false
+		}
+		keys = append(keys, key)
+		return true
+	}
+	if s.bytes != nil {
+		s.bytes.Range([]byte(prefix), nil, func(key []byte) bool {
+			return collect(string(key))
+		})
+	} else {
+		s.runes.Range([]rune(prefix), nil, func(key []rune) bool {
+			return collect(string(key))
+		})
+	}
+	return keys, truncated
+}
+
+// Request metrics, published through expvar by main under "tripod".
+type metrics struct {
+	requests  *expvar.Map // by endpoint
+	errors    *expvar.Map // by endpoint
+	latencyUs *expvar.Map // total microseconds by endpoint
+	results   *expvar.Int // total completions returned
+}
+
+// Creates the metrics of a server as entries of vars, which the caller
+// publishes, so that every server has its own.
+func newMetrics(vars *expvar.Map) *metrics {
+	m := &metrics{
+		requests:  new(expvar.Map).Init(),
+		errors:    new(expvar.Map).Init(),
+		latencyUs: new(expvar.Map).Init(),
+		results:   new(expvar.Int),
+	}
+	vars.Set("requests", m.requests)
+	vars.Set("errors", m.errors)
+	vars.Set("latency_us", m.latencyUs)
+	vars.Set("completions", m.results)
+	return m
+}
+
+// Serves the prefix stores over HTTP.
+type server struct {
+	stores  map[string]*store
+	limits  limits
+	metrics *metrics
+}
+
+// Returns the handler serving the API and the metrics.
+func (srv *server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/{store}/complete", srv.instrument("complete", srv.handleComplete))
+	mux.HandleFunc("GET /v1/{store}/exists", srv.instrument("exists", srv.handleExists))
+	mux.HandleFunc("POST /v1/{store}/put", srv.instrument("put", srv.handlePut))
+	mux.Handle("GET /debug/vars", expvar.Handler())
+	return mux
+}
+
+// Records the count, errors and latency of requests to an endpoint.
+func (srv *server) instrument(endpoint string, h func(w http.ResponseWriter, r *http.Request) int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		status := h(w, r)
+		srv.metrics.requests.Add(endpoint, 1)
+		if status >= 400 {
+			srv.metrics.errors.Add(endpoint, 1)
+		}
+		srv.metrics.latencyUs.Add(endpoint, time.Since(start).Microseconds())
+	}
+}
+
+// Writes v as JSON with the status and returns the status.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) int {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+	return status
+}
+
+func writeError(w http.ResponseWriter, status int, format string, args ...interface{}) int {
+	return writeJSON(w, status, map[string]string{"error": fmt.Sprintf(format, args...)})
+}
+
+// Returns the store named in the request path, writing an error response
+// when there is no such store.
+func (srv *server) lookup(w http.ResponseWriter, r *http.Request) (*store, int) {
+	name := r.PathValue("store")
+	s, ok := srv.stores[name]
+	if !ok {
+		return nil, writeError(w, http.StatusNotFound, "unknown store %q", name)
+	}
+	return s, 0
+}
+
+type completeResponse struct {
+	Store       string   `json:"store"`
+	Prefix      string   `json:"prefix"`
+	Completions []string `json:"completions"`
+	Truncated   bool     `json:"truncated"`
+}
+
+// GET /v1/{store}/complete?prefix=..&limit=..
+func (srv *server) handleComplete(w http.ResponseWriter, r *http.Request) int {
+	s, status := srv.lookup(w, r)
+	if s == nil {
+		return status
+	}
+
+	limit := srv.limits.defaultLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil || limit < 1 {
+			return writeError(w, http.StatusBadRequest, "limit should be a positive integer")
+		}
+	}
+	if limit > srv.limits.maxLimit {
+		limit = srv.limits.maxLimit
+	}
+
+	prefix := r.URL.Query().Get("prefix")
+	completions, truncated := s.complete(prefix, limit)
+	srv.metrics.results.Add(int64(len(completions)))
+
+	return writeJSON(w, http.StatusOK, completeResponse{
+		Store:       r.PathValue("store"),
+		Prefix:      prefix,
+		Completions: completions,
+		Truncated:   truncated,
+	})
+}
+
+type existsResponse struct {
+	Key    string `json:"key"`
+	Exists bool   `json:"exists"`
+}
+
+// GET /v1/{store}/exists?key=..
+func (srv *server) handleExists(w http.ResponseWriter, r *http.Request) int {
+	s, status := srv.lookup(w, r)
+	if s == nil {
+		return status
+	}
+
+	key := r.URL.Query().Get("key")
+	return writeJSON(w, http.StatusOK, existsResponse{Key: key, Exists: s.exists(key)})
+}
+
+type putRequest struct {
+	Key string `json:"key"`
+}
+
+type putResponse struct {
+	Key   string `json:"key"`
+	Added bool   `json:"added"`
+}
+
+// POST /v1/{store}/put with body {"key": ".."}
+func (srv *server) handlePut(w http.ResponseWriter, r *http.Request) int {
+	s, status := srv.lookup(w, r)
+	if s == nil {
+		return status
+	}
+
+	var req putRequest
+	body := http.MaxBytesReader(w, r.Body, srv.limits.maxBodyBytes)
+	if err := json.NewDecoder(body).Decode(&req); err != nil {
+		return writeError(w, http.StatusBadRequest, "invalid body: %v", err)
+	}
+	if req.Key == "" {
+		return writeError(w, http.StatusBadRequest, "key should not be empty")
+	}
+
+	added, err := s.put(req.Key)
+	if err != nil {
+		return writeError(w, http.StatusBadRequest, "%v", err)
+	}
+	return writeJSON(w, http.StatusOK, putResponse{Key: req.Key, Added: added})
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"encoding/json"
+	"expvar"
+	"github.com/arpitbbhayani/tripod"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newTestServer() *httptest.Server {
+	ts, _ := newTestServerWithMetrics()
+	return ts
+}
+
+func newTestServerWithMetrics() (*httptest.Server, *expvar.Map) {
+	words := tripod.CreatePrefixStoreByteTrie(16)
+	for _, key := range []string{"go", "gone", "good", "gopher", "is"} {
+		words.Put([]byte(key))
+	}
+
+	vars := new(expvar.Map).Init()
+	srv := &server{
+		stores: map[string]*store{
+			"words":   {bytes: words},
+			"scratch": {runes: tripod.CreatePrefixStoreRuneTrie(8)},
+		},
+		limits:  limits{defaultLimit: 2, maxLimit: 3, maxBodyBytes: 64},
+		metrics: newMetrics(vars),
+	}
+	return httptest.NewServer(srv.handler()), vars
+}
+
+func getJSON(t *testing.T, url string, v interface{}) int {
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("GET %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+	json.NewDecoder(resp.Body).Decode(v)
+	return resp.StatusCode
+}
+
+func TestComplete(t *testing.T) {
+	ts := newTestServer()
+	defer ts.Close()
+
+	var resp completeResponse
+	if status := getJSON(t, ts.URL+"/v1/words/complete?prefix=go", &resp); status != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, status)
+	}
+	if strings.Join(resp.Completions, ",") != "go,gone" || !resp.Truncated {
+		t.Errorf("expected default limit of sorted completions, got %v (truncated %t)", resp.Completions, resp.Truncated)
+	}
+
+	getJSON(t, ts.URL+"/v1/words/complete?prefix=goo&limit=1", &resp)
+	if strings.Join(resp.Completions, ",") != "good" || resp.Truncated {
+		t.Errorf("expected the only completion, got %v (truncated %t)", resp.Completions, resp.Truncated)
+	}
+	getJSON(t, ts.URL+"/v1/words/complete?prefix=h", &resp)
+	if len(resp.Completions) != 0 || resp.Truncated {
+		t.Errorf("expected no completion, got %v (truncated %t)", resp.Completions, resp.Truncated)
+	}
+
+	getJSON(t, ts.URL+"/v1/words/complete?prefix=go&limit=100", &resp)
+	if len(resp.Completions) != 3 {
+		t.Errorf("limit should be capped to %d, got %d completions", 3, len(resp.Completions))
+	}
+
+	if status := getJSON(t, ts.URL+"/v1/words/complete?prefix=go&limit=x", &resp); status != http.StatusBadRequest {
+		t.Errorf("invalid limit should return status %d, got %d", http.StatusBadRequest, status)
+	}
+	if status := getJSON(t, ts.URL+"/v1/nope/complete?prefix=go", &resp); status != http.StatusNotFound {
+		t.Errorf("unknown store should return status %d, got %d", http.StatusNotFound, status)
+	}
+}
+
+func TestPutAndExists(t *testing.T) {
+	ts := newTestServer()
+	defer ts.Close()
+
+	post := func(body string) (int, putResponse) {
+		var out putResponse
+		resp, err := http.Post(ts.URL+"/v1/scratch/put", "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("POST: %v", err)
+		}
+		defer resp.Body.Close()
+		json.NewDecoder(resp.Body).Decode(&out)
+		return resp.StatusCode, out
+	}
+
+	if status, out := post(`{"key": "café"}`); status != http.StatusOK || !out.Added {
+		t.Errorf("expected key to be added, got status %d and %v", status, out)
+	}
+	if status, out := post(`{"key": "café"}`); status != http.StatusOK || out.Added {
+		t.Errorf("expected existing key not to be added, got status %d and %v", status, out)
+	}
+	if status, _ := post(`{"key": "waytoolongkey"}`); status != http.StatusBadRequest {
+		t.Errorf("key longer than max size should return status %d, got %d", http.StatusBadRequest, status)
+	}
+	if status, _ := post(`{"key": "` + strings.Repeat("a", 100) + `"}`); status != http.StatusBadRequest {
+		t.Errorf("body larger than limit should return status %d, got %d", http.StatusBadRequest, status)
+	}
+
+	var exists existsResponse
+	getJSON(t, ts.URL+"/v1/scratch/exists?key=caf%C3%A9", &exists)
+	if !exists.Exists {
+		t.Errorf("key %s should exist after put", "café")
+	}
+	getJSON(t, ts.URL+"/v1/scratch/exists?key=caf", &exists)
+	if exists.Exists {
+		t.Errorf("key %s should not exist", "caf")
+	}
+}
+
+func TestMetricsPerServer(t *testing.T) {
+	first, firstVars := newTestServerWithMetrics()
+	defer first.Close()
+	second, secondVars := newTestServerWithMetrics()
+	defer second.Close()
+
+	var resp completeResponse
+	getJSON(t, first.URL+"/v1/words/complete?prefix=go", &resp)
+	getJSON(t, second.URL+"/v1/words/complete?prefix=go", &resp)
+	getJSON(t, second.URL+"/v1/words/complete?prefix=go", &resp)
+
+	for _, tc := range []struct {
+		vars     *expvar.Map
+		requests string
+	}{{firstVars, "1"}, {secondVars, "2"}} {
+		requests := tc.vars.Get("requests").(*expvar.Map).Get("complete")
+		if requests == nil || requests.String() != tc.requests {
+			t.Errorf("expected %s requests, got %v", tc.requests, requests)
+		}
+	}
+}
@@ -0,0 +1,294 @@
+// Command tripod builds, queries and inspects serialized prefix stores.
+//
+// Usage:
+//
+//	tripod build [-runes] [-max n] [-format text|tsv|ndjson] -o store.trie [wordlist ...]
+//	tripod query -prefix p [-limit n] store.trie
+//	tripod exists store.trie key
+//	tripod stats store.trie
+//	tripod dump store.trie
+//
+// build reads the word lists, or the standard input when none is given, and
+// writes the prefix store serialized by the tripod library. Every other
+// command reads a store written by build, of either kind.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"github.com/arpitbbhayani/tripod"
+	"io"
+	"os"
+	"sort"
+)
+
+const usage = `usage: tripod <command> [arguments]
+
+commands:
+  build   build a store file from word lists
+  query   list the keys of a store file having a prefix
+  exists  test if a key is present in a store file
+  stats   show node count, key count and memory of a store file
+  dump    write all the keys of a store file, one per line
+
+Run 'tripod <command> -h' for the arguments of a command.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	commands := map[string]func(args []string){
+		"build":  build,
+		"query":  query,
+		"exists": exists,
+		"stats":  stats,
+		"dump":   dump,
+	}
+	command, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "tripod: unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+	command(os.Args[2:])
+}
+
+// Prints the error and exits.
+func fatalf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "tripod: "+format+"\n", args...)
+	os.Exit(1)
+}
+
+// Either kind of prefix store, as read from a store file.
+type store struct {
+	bytes *tripod.PrefixStoreByteTrie
+	runes *tripod.PrefixStoreRuneTrie
+}
+
+// Reads the store file at path, whichever kind of store it holds.
+func openStore(path string) *store {
+	f, err := os.Open(path)
+	if err != nil {
+		fatalf("%v", err)
+	}
+	defer f.Close()
+
+	bytes, runes, err := tripod.ReadPrefixStore(f)
+	if err != nil {
+		fatalf("reading %s: %v", path, err)
+	}
+	return &store{bytes: bytes, runes: runes}
+}
+
+func (s *store) kind() string {
+	if s.bytes != nil {
+		return "PrefixStoreByteTrie"
+	}
+	return "PrefixStoreRuneTrie"
+}
+
+func (s *store) exists(key string) bool {
+	if s.bytes != nil {
+		return s.bytes.Exists([]byte(key))
+	}
+	return s.runes.Exists([]rune(key))
+}
+
+// Calls fn with the keys having the prefix in ascending order, at most limit
+// of them unless limit is zero or less. The walk stops at the limit, hence
+// only the keys printed are visited.
+func (s *store) prefixSearch(prefix string, limit int, fn func(key string)) {
+	found := 0
+	visit := func(key string, isKey bool) tripod.WalkAction {
+		if !isKey {
+			return tripod.WalkContinue
+		}
+		fn(key)
+		found++
+		if found == limit {
+			return tripod.WalkStop
+		}
+		return tripod.WalkContinue
+	}
+	if s.bytes != nil {
+		s.bytes.Walk([]byte(prefix), func(path []byte, isKey bool) tripod.WalkAction {
+			return visit(string(path), isKey)
+		})
+	} else {
+		s.runes.Walk([]rune(prefix), func(path []rune, isKey bool) tripod.WalkAction {
+			return visit(string(path), isKey)
+		})
+	}
+}
+
+func (s *store) stats() tripod.PrefixStoreStats {
+	if s.bytes != nil {
+		return s.bytes.Stats()
+	}
+	return s.runes.Stats()
+}
+
+// Writes the keys having the prefix, at most limit of them unless limit is
+// zero or less, one per line to the standard output.
+func printKeys(s *store, prefix string, limit int) {
+	w := bufio.NewWriter(os.Stdout)
+	s.prefixSearch(prefix, limit, func(key string) {
+		fmt.Fprintln(w, key)
+	})
+	if err := w.Flush(); err != nil {
+		fatalf("%v", err)
+	}
+}
+
+func build(args []string) {
+	fs := flag.NewFlagSet("build", flag.ExitOnError)
+	output := fs.String("o", "", "path of the store file to write (required)")
+	runes := fs.Bool("runes", false, "build a PrefixStoreRuneTrie instead of a PrefixStoreByteTrie")
+	maxKeySize := fs.Int("max", 128, "maximum size of a key in bytes, or runes with -runes")
+	format := fs.String("format", "text", "format of the word lists: text, tsv or ndjson")
+	keyField := fs.String("key-field", "key", "field holding the key with -format ndjson")
+	fs.Parse(args)
+
+	if *output == "" {
+		fatalf("build: -o is required")
+	}
+
+	opts := tripod.LoadOptions{KeyField: *keyField, MaxErrors: 10}
+	switch *format {
+	case "text":
+		opts.Format = tripod.LoadFormatText
+	case "tsv":
+		opts.Format = tripod.LoadFormatTSV
+	case "ndjson":
+		opts.Format = tripod.LoadFormatNDJSON
+	default:
+		fatalf("build: unknown format %q", *format)
+	}
+
+	var byteTrie *tripod.PrefixStoreByteTrie
+	var runeTrie *tripod.PrefixStoreRuneTrie
+	load := func(name string, r io.Reader) {
+		var stats tripod.LoadStats
+		var err error
+		if *runes {
+			stats, err = runeTrie.LoadFrom(r, opts)
+		} else {
+			stats, err = byteTrie.LoadFrom(r, opts)
+		}
+		if err != nil {
+			fatalf("reading %s: %v", name, err)
+		}
+		for _, lineErr := range stats.Errors {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", name, lineErr)
+		}
+		fmt.Fprintf(os.Stderr, "%s: %d lines, %d added, %d duplicates, %d failed\n",
+			name, stats.Lines, stats.Added, stats.Duplicates, stats.Failed)
+	}
+
+	if *runes {
+		runeTrie = tripod.CreatePrefixStoreRuneTrie(*maxKeySize)
+	} else {
+		byteTrie = tripod.CreatePrefixStoreByteTrie(*maxKeySize)
+	}
+
+	if fs.NArg() == 0 {
+		load("stdin", os.Stdin)
+	}
+	for _, path := range fs.Args() {
+		f, err := os.Open(path)
+		if err != nil {
+			fatalf("%v", err)
+		}
+		load(path, f)
+		f.Close()
+	}
+
+	f, err := os.Create(*output)
+	if err != nil {
+		fatalf("%v", err)
+	}
+	if *runes {
+		_, err = runeTrie.WriteTo(f)
+	} else {
+		_, err = byteTrie.WriteTo(f)
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		fatalf("writing %s: %v", *output, err)
+	}
+}
+
+func query(args []string) {
+	fs := flag.NewFlagSet("query", flag.ExitOnError)
+	prefix := fs.String("prefix", "", "prefix to search for")
+	limit := fs.Int("limit", 0, "maximum number of keys to print, 0 prints all")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fatalf("usage: tripod query -prefix p [-limit n] store.trie")
+	}
+
+	printKeys(openStore(fs.Arg(0)), *prefix, *limit)
+}
+
+func exists(args []string) {
+	fs := flag.NewFlagSet("exists", flag.ExitOnError)
+	fs.Parse(args)
+
+	if fs.NArg() != 2 {
+		fatalf("usage: tripod exists store.trie key")
+	}
+
+	// Like grep, the exit status tells if the key was found.
+	found := openStore(fs.Arg(0)).exists(fs.Arg(1))
+	fmt.Println(found)
+	if !found {
+		os.Exit(1)
+	}
+}
+
+func stats(args []string) {
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fatalf("usage: tripod stats store.trie")
+	}
+
+	s := openStore(fs.Arg(0))
+	stats := s.stats()
+	fmt.Printf("kind:            %s\n", s.kind())
+	fmt.Printf("keys:            %d\n", stats.KeyCount)
+	fmt.Printf("nodes:           %d\n", stats.NodeCount)
+	fmt.Printf("max depth:       %d\n", stats.MaxDepth)
+	fmt.Printf("avg depth:       %.2f\n", stats.AvgDepth)
+	fmt.Printf("node bytes:      %d\n", stats.NodeBytes)
+	fmt.Printf("map bytes:       %d\n", stats.MapBytes)
+	fmt.Printf("estimated bytes: %d\n", stats.EstimatedBytes)
+	fmt.Printf("children:       ")
+	counts := make([]int, 0, len(stats.ChildrenHistogram))
+	for children := range stats.ChildrenHistogram {
+		counts = append(counts, children)
+	}
+	sort.Ints(counts)
+	for _, children := range counts {
+		fmt.Printf(" %d:%d", children, stats.ChildrenHistogram[children])
+	}
+	fmt.Println()
+}
+
+func dump(args []string) {
+	fs := flag.NewFlagSet("dump", flag.ExitOnError)
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fatalf("usage: tripod dump store.trie")
+	}
+
+	printKeys(openStore(fs.Arg(0)), "", 0)
+}
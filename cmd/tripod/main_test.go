@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Runs main instead of the tests when the test binary is run by runTripod.
+func TestMain(m *testing.M) {
+	if os.Getenv("TRIPOD_TEST_MAIN") == "1" {
+		main()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// Runs the tripod command with args and stdin, and returns its standard
+// output and exit status.
+func runTripod(t *testing.T, stdin string, args ...string) (string, int) {
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Env = append(os.Environ(), "TRIPOD_TEST_MAIN=1")
+	cmd.Stdin = strings.NewReader(stdin)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		return stdout.String(), exitErr.ExitCode()
+	}
+	if err != nil {
+		t.Fatalf("running tripod %v: %v", args, err)
+	}
+	return stdout.String(), 0
+}
+
+func TestCommands(t *testing.T) {
+	for _, kind := range []string{"bytes", "runes"} {
+		path := filepath.Join(t.TempDir(), "words.trie")
+		args := []string{"build", "-o", path}
+		if kind == "runes" {
+			args = append(args, "-runes")
+		}
+		if _, status := runTripod(t, "gopher\ngo\nrust\ngolang\ngone\n", args...); status != 0 {
+			t.Fatalf("%s: build: expected exit status %d, got %d", kind, 0, status)
+		}
+
+		if out, _ := runTripod(t, "", "query", "-prefix", "go", path); out != "go\ngolang\ngone\ngopher\n" {
+			t.Errorf("%s: query: unexpected output %q", kind, out)
+		}
+		if out, _ := runTripod(t, "", "query", "-prefix", "go", "-limit", "2", path); out != "go\ngolang\n" {
+			t.Errorf("%s: query with limit: unexpected output %q", kind, out)
+		}
+		if out, _ := runTripod(t, "", "query", "-prefix", "java", path); out != "" {
+			t.Errorf("%s: query of missing prefix: unexpected output %q", kind, out)
+		}
+
+		if out, status := runTripod(t, "", "exists", path, "rust"); out != "true\n" || status != 0 {
+			t.Errorf("%s: exists: expected %q and exit status %d, got %q and %d", kind, "true\n", 0, out, status)
+		}
+		if out, status := runTripod(t, "", "exists", path, "gop"); out != "false\n" || status != 1 {
+			t.Errorf("%s: exists: expected %q and exit status %d, got %q and %d", kind, "false\n", 1, out, status)
+		}
+
+		out, _ := runTripod(t, "", "stats", path)
+		if !strings.Contains(out, "keys:            5\n") || !strings.Contains(out, "max depth:       6\n") {
+			t.Errorf("%s: stats: unexpected output %q", kind, out)
+		}
+
+		if out, _ := runTripod(t, "", "dump", path); out != "go\ngolang\ngone\ngopher\nrust\n" {
+			t.Errorf("%s: dump: unexpected output %q", kind, out)
+		}
+	}
+}
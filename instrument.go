@@ -0,0 +1,261 @@
+package tripod
+
+import (
+	"container/list"
+	"encoding/json"
+	"sync/atomic"
+	"time"
+)
+
+// Upper bounds of the buckets of the latency histograms, in nanoseconds.
+var latencyBucketBounds = []int64{
+	500, 1e3, 2.5e3, 5e3, 1e4, 2.5e4, 5e4, 1e5, 2.5e5, 5e5,
+	1e6, 1e7, 1e8, 1e9,
+}
+
+// Upper bounds of the buckets of the PrefixSearch result size histogram.
+var resultBucketBounds = []int64{0, 1, 2, 5, 10, 20, 50, 100, 200, 500, 1000, 10000}
+
+// A histogram of int64 observations over fixed buckets, safe for concurrent
+// use without locks or allocations.
+type histogram struct {
+	bounds []int64
+	counts []atomic.Uint64 // one per bound, and one above all bounds
+	count  atomic.Uint64
+	sum    atomic.Int64
+}
+
+func createHistogram(bounds []int64) *histogram {
+	return &histogram{
+		bounds: bounds,
+		counts: make([]atomic.Uint64, len(bounds)+1),
+	}
+}
+
+func (h *histogram) observe(v int64) {
+	i := 0
+	for i < len(h.bounds) && v > h.bounds[i] {
+		i++
+	}
+	h.counts[i].Add(1)
+	h.count.Add(1)
+	h.sum.Add(v)
+}
+
+func (h *histogram) snapshot() HistogramSnapshot {
+	counts := make([]uint64, len(h.counts))
+	for i := range h.counts {
+		counts[i] = h.counts[i].Load()
+	}
+	return HistogramSnapshot{
+		Bounds: h.bounds,
+		Counts: counts,
+		Count:  h.count.Load(),
+		Sum:    h.sum.Load(),
+	}
+}
+
+// Represents a point in time copy of a histogram.
+type HistogramSnapshot struct {
+	// Inclusive upper bounds of the buckets, in ascending order.
+	Bounds []int64
+
+	// Number of observations per bucket: Counts[i] is the number of
+	// observations in (Bounds[i-1], Bounds[i]], and the last one the number
+	// of observations above all bounds.
+	Counts []uint64
+
+	// Number and sum of all the observations.
+	Count uint64
+	Sum   int64
+}
+
+// Represents the metrics recorded by an instrumented store. The methods of
+// StoreMetrics are safe for concurrent use, except that the number of keys
+// is read from the wrapped store, hence only as safe as its Count.
+// StoreMetrics implements expvar.Var, hence it can be published as is with
+// expvar.Publish; see package tripodprom for a Prometheus collector.
+type StoreMetrics struct {
+	count          func() int
+	put            *histogram
+	exists         *histogram
+	prefixSearch   *histogram
+	delete         *histogram
+	resultsPerCall *histogram
+}
+
+func createStoreMetrics(count func() int) *StoreMetrics {
+	return &StoreMetrics{
+		count:          count,
+		put:            createHistogram(latencyBucketBounds),
+		exists:         createHistogram(latencyBucketBounds),
+		prefixSearch:   createHistogram(latencyBucketBounds),
+		delete:         createHistogram(latencyBucketBounds),
+		resultsPerCall: createHistogram(resultBucketBounds),
+	}
+}
+
+// Represents a point in time copy of StoreMetrics. The latency histograms are
+// in nanoseconds, and their Count is the number of calls of the method.
+type StoreMetricsSnapshot struct {
+	Keys                int64
+	Put                 HistogramSnapshot
+	Exists              HistogramSnapshot
+	PrefixSearch        HistogramSnapshot
+	Delete              HistogramSnapshot
+	PrefixSearchResults HistogramSnapshot
+}
+
+// Returns a copy of the metrics recorded so far, along with the number of
+// keys the wrapped store holds now, as returned by its Count. The metrics
+// are read one by one while calls may be recorded, hence they are not
+// guaranteed to be mutually consistent.
+func (m *StoreMetrics) Snapshot() StoreMetricsSnapshot {
+	return StoreMetricsSnapshot{
+		Keys:                int64(m.count()),
+		Put:                 m.put.snapshot(),
+		Exists:              m.exists.snapshot(),
+		PrefixSearch:        m.prefixSearch.snapshot(),
+		Delete:              m.delete.snapshot(),
+		PrefixSearchResults: m.resultsPerCall.snapshot(),
+	}
+}
+
+// Returns the snapshot of the metrics as JSON, as required by expvar.Var.
+func (m *StoreMetrics) String() string {
+	b, _ := json.Marshal(m.Snapshot())
+	return string(b)
+}
+
+// Represents a BytePrefixStore recording metrics of the calls made to
+// another one: the number and latency of the calls, the number of results
+// of PrefixSearch, and the number of keys.
+// The wrapped store itself is left untouched, hence stores that are not
+// instrumented do not pay for it. InstrumentedBytePrefixStore is as safe
+// for concurrent use as the store it wraps.
+type InstrumentedBytePrefixStore struct {
+	store   BytePrefixStore
+	metrics *StoreMetrics
+}
+
+// Creates and returns reference to a new instance of
+// InstrumentedBytePrefixStore recording the calls made to store. The number
+// of keys is read from store.Count() whenever the metrics are read, hence it
+// accounts for keys the store removes on its own, like expired or evicted
+// ones, and for changes made to store directly.
+func CreateInstrumentedBytePrefixStore(store BytePrefixStore) *InstrumentedBytePrefixStore {
+	return &InstrumentedBytePrefixStore{
+		store:   store,
+		metrics: createStoreMetrics(store.Count),
+	}
+}
+
+// Returns the metrics recorded by the store.
+func (s *InstrumentedBytePrefixStore) Metrics() *StoreMetrics {
+	return s.metrics
+}
+
+// Adds the key to the wrapped store and returns its result.
+func (s *InstrumentedBytePrefixStore) Put(key []byte) (bool, error) {
+	start := time.Now()
+	newlyAdded, err := s.store.Put(key)
+	s.metrics.put.observe(int64(time.Since(start)))
+	return newlyAdded, err
+}
+
+// Checks and returns if given key is present in the wrapped store.
+func (s *InstrumentedBytePrefixStore) Exists(key []byte) bool {
+	start := time.Now()
+	found := s.store.Exists(key)
+	s.metrics.exists.observe(int64(time.Since(start)))
+	return found
+}
+
+// Does the prefix search on the wrapped store and returns its result.
+func (s *InstrumentedBytePrefixStore) PrefixSearch(prefix []byte) *list.List {
+	start := time.Now()
+	entries := s.store.PrefixSearch(prefix)
+	s.metrics.prefixSearch.observe(int64(time.Since(start)))
+	s.metrics.resultsPerCall.observe(int64(entries.Len()))
+	return entries
+}
+
+// Removes the key from the wrapped store and returns if the key was present.
+func (s *InstrumentedBytePrefixStore) Delete(key []byte) bool {
+	start := time.Now()
+	deleted := s.store.Delete(key)
+	s.metrics.delete.observe(int64(time.Since(start)))
+	return deleted
+}
+
+// Returns the number of keys present in the wrapped store.
+func (s *InstrumentedBytePrefixStore) Count() int {
+	return s.store.Count()
+}
+
+// Represents a RunePrefixStore recording metrics of the calls made to
+// another one, like InstrumentedBytePrefixStore does for BytePrefixStore.
+type InstrumentedRunePrefixStore struct {
+	store   RunePrefixStore
+	metrics *StoreMetrics
+}
+
+// Creates and returns reference to a new instance of
+// InstrumentedRunePrefixStore recording the calls made to store. The number
+// of keys is read from store.Count() whenever the metrics are read, hence it
+// accounts for keys the store removes on its own, like expired or evicted
+// ones, and for changes made to store directly.
+func CreateInstrumentedRunePrefixStore(store RunePrefixStore) *InstrumentedRunePrefixStore {
+	return &InstrumentedRunePrefixStore{
+		store:   store,
+		metrics: createStoreMetrics(store.Count),
+	}
+}
+
+// Returns the metrics recorded by the store.
+func (s *InstrumentedRunePrefixStore) Metrics() *StoreMetrics {
+	return s.metrics
+}
+
+// Adds the key to the wrapped store and returns its result.
+func (s *InstrumentedRunePrefixStore) Put(key []rune) (bool, error) {
+	start := time.Now()
+	newlyAdded, err := s.store.Put(key)
+	s.metrics.put.observe(int64(time.Since(start)))
+	return newlyAdded, err
+}
+
+// Checks and returns if given key is present in the wrapped store.
+func (s *InstrumentedRunePrefixStore) Exists(key []rune) bool {
+	start := time.Now()
+	found := s.store.Exists(key)
+	s.metrics.exists.observe(int64(time.Since(start)))
+	return found
+}
+
+// Does the prefix search on the wrapped store and returns its result.
+func (s *InstrumentedRunePrefixStore) PrefixSearch(prefix []rune) *list.List {
+	start := time.Now()
+	entries := s.store.PrefixSearch(prefix)
+	s.metrics.prefixSearch.observe(int64(time.Since(start)))
+	s.metrics.resultsPerCall.observe(int64(entries.Len()))
+	return entries
+}
+
+// Removes the key from the wrapped store and returns if the key was present.
+func (s *InstrumentedRunePrefixStore) Delete(key []rune) bool {
+	start := time.Now()
+	deleted := s.store.Delete(key)
+	s.metrics.delete.observe(int64(time.Since(start)))
+	return deleted
+}
+
+// Returns the number of keys present in the wrapped store.
+func (s *InstrumentedRunePrefixStore) Count() int {
+	return s.store.Count()
+}
+
+var (
+	_ BytePrefixStore = (*InstrumentedBytePrefixStore)(nil)
+	_ RunePrefixStore = (*InstrumentedRunePrefixStore)(nil)
+)
@@ -0,0 +1,236 @@
+package tripod
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Represents the format of the stream consumed by LoadFrom.
+type LoadFormat int
+
+const (
+	// One key per line. The complete line (without the line terminator) is
+	// the key.
+	LoadFormatText LoadFormat = iota
+
+	// Tab separated columns per line: key, then optionally weight and value.
+	// An empty weight column is allowed when only a value is present.
+	LoadFormatTSV
+
+	// One JSON object per line. The key, weight and value are read from the
+	// fields named in LoadOptions.
+	LoadFormatNDJSON
+)
+
+// Options that control how LoadFrom parses the stream. The zero value reads
+// plain text with one key per line.
+type LoadOptions struct {
+	Format LoadFormat
+
+	// Names of the JSON fields holding the key, weight and value when Format
+	// is LoadFormatNDJSON. Empty names default to "key", "weight" and
+	// "value".
+	KeyField    string
+	WeightField string
+	ValueField  string
+
+	// Maximum number of line errors retained in LoadStats.Errors. Lines
+	// beyond this limit are still counted in LoadStats.Failed.
+	// 0 retains every line error.
+	MaxErrors int
+
+	// If non nil, is called for every line whose key was put in the
+	// PrefixStore, so that callers can keep weights or values alongside.
+	OnRecord func(record LoadRecord)
+}
+
+// A single parsed line of the stream.
+type LoadRecord struct {
+	Line      int
+	Key       string
+	Weight    float64
+	HasWeight bool
+	Value     string
+}
+
+// Represents a line that could not be loaded in the PrefixStore.
+type LoadError struct {
+	Line int
+	Err  error
+}
+
+func (e *LoadError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+// Statistics of a LoadFrom call.
+type LoadStats struct {
+	// Number of lines read from the stream.
+	Lines int
+
+	// Number of keys newly added to the PrefixStore.
+	Added int
+
+	// Number of keys that were already present in the PrefixStore.
+	Duplicates int
+
+	// Number of blank lines skipped.
+	Skipped int
+
+	// Number of lines that could not be parsed or put.
+	Failed int
+
+	// Errors for the lines that failed, in the order they were encountered.
+	Errors []*LoadError
+}
+
+// Streams keys from r and puts each of them in the PrefixStore.
+// A line that cannot be parsed, or whose key is longer than
+// maxKeySizeInBytes, is recorded in the returned LoadStats and does not abort
+// the load. A non nil error is returned only if reading from r fails.
+func (t *PrefixStoreByteTrie) LoadFrom(r io.Reader, opts LoadOptions) (LoadStats, error) {
+	return loadRecords(r, opts, func(record *LoadRecord) (bool, error) {
+		return t.Put([]byte(record.Key))
+	})
+}
+
+// Streams keys from r and puts each of them in the PrefixStore.
+// A line that cannot be parsed, or whose key is longer than
+// maxKeySizeInRunes, is recorded in the returned LoadStats and does not abort
+// the load. A non nil error is returned only if reading from r fails.
+func (t *PrefixStoreRuneTrie) LoadFrom(r io.Reader, opts LoadOptions) (LoadStats, error) {
+	return loadRecords(r, opts, func(record *LoadRecord) (bool, error) {
+		return t.Put([]rune(record.Key))
+	})
+}
+
+// Reads r line by line, parses every line as per opts and hands the record
+// over to put, accumulating the outcome in LoadStats.
+func loadRecords(r io.Reader, opts LoadOptions, put func(*LoadRecord) (bool, error)) (LoadStats, error) {
+	var stats LoadStats
+	reader := bufio.NewReader(r)
+
+	fail := func(line int, err error) {
+		stats.Failed++
+		if opts.MaxErrors == 0 || len(stats.Errors) < opts.MaxErrors {
+			stats.Errors = append(stats.Errors, &LoadError{Line: line, Err: err})
+		}
+	}
+
+	for {
+		line, readErr := reader.ReadString('\n')
+		if readErr != nil && readErr != io.EOF {
+			return stats, readErr
+		}
+		if len(line) == 0 && readErr == io.EOF {
+			return stats, nil
+		}
+
+		stats.Lines++
+		line = strings.TrimSuffix(line, "\n")
+		line = strings.TrimSuffix(line, "\r")
+
+		if strings.TrimSpace(line) == "" {
+			stats.Skipped++
+		} else {
+			record := LoadRecord{Line: stats.Lines}
+			if err := parseRecord(line, opts, &record); err != nil {
+				fail(stats.Lines, err)
+			} else if newlyAdded, err := put(&record); err != nil {
+				fail(stats.Lines, err)
+			} else {
+				if newlyAdded {
+					stats.Added++
+				} else {
+					stats.Duplicates++
+				}
+				if opts.OnRecord != nil {
+					opts.OnRecord(record)
+				}
+			}
+		}
+
+		if readErr == io.EOF {
+			return stats, nil
+		}
+	}
+}
+
+// Parses a single non blank line as per opts.Format into record.
+func parseRecord(line string, opts LoadOptions, record *LoadRecord) error {
+	switch opts.Format {
+	case LoadFormatText:
+		record.Key = line
+	case LoadFormatTSV:
+		columns := strings.SplitN(line, "\t", 3)
+		record.Key = columns[0]
+		if len(columns) > 1 && columns[1] != "" {
+			weight, err := strconv.ParseFloat(columns[1], 64)
+			if err != nil {
+				return fmt.Errorf("invalid weight %q", columns[1])
+			}
+			record.Weight, record.HasWeight = weight, true
+		}
+		if len(columns) > 2 {
+			record.Value = columns[2]
+		}
+	case LoadFormatNDJSON:
+		return parseNDJSONRecord(line, opts, record)
+	default:
+		return fmt.Errorf("unknown load format %d", opts.Format)
+	}
+
+	if record.Key == "" {
+		return fmt.Errorf("empty key")
+	}
+	return nil
+}
+
+// Parses a line holding a JSON object into record.
+func parseNDJSONRecord(line string, opts LoadOptions, record *LoadRecord) error {
+	keyField, weightField, valueField := opts.KeyField, opts.WeightField, opts.ValueField
+	if keyField == "" {
+		keyField = "key"
+	}
+	if weightField == "" {
+		weightField = "weight"
+	}
+	if valueField == "" {
+		valueField = "value"
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(line), &fields); err != nil {
+		return err
+	}
+
+	rawKey, ok := fields[keyField]
+	if !ok {
+		return fmt.Errorf("missing field %q", keyField)
+	}
+	if err := json.Unmarshal(rawKey, &record.Key); err != nil {
+		return fmt.Errorf("field %q should be a string", keyField)
+	}
+	if record.Key == "" {
+		return fmt.Errorf("empty key")
+	}
+
+	if rawWeight, ok := fields[weightField]; ok {
+		if err := json.Unmarshal(rawWeight, &record.Weight); err != nil {
+			return fmt.Errorf("field %q should be a number", weightField)
+		}
+		record.HasWeight = true
+	}
+
+	if rawValue, ok := fields[valueField]; ok {
+		// String values are unquoted, anything else is kept as raw JSON.
+		if err := json.Unmarshal(rawValue, &record.Value); err != nil {
+			record.Value = string(rawValue)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package tripod
+
+import (
+	"container/list"
+	"fmt"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+	"unicode"
+)
+
+// Maps a key to its normalized form, e.g. folding its case, so that keys
+// differing only in that respect are treated as the same key.
+// A Normalizer must not modify the key passed to it.
+type Normalizer func(key []rune) []rune
+
+// Normalizer applying Unicode full case folding, e.g. "CAFÉ" and "Straße"
+// become "café" and "strasse".
+func FoldCase(key []rune) []rune {
+	return []rune(cases.Fold().String(string(key)))
+}
+
+// Normalizer applying Unicode canonical composition (NFC), e.g. "e" followed
+// by a combining acute accent becomes "é".
+func NFC(key []rune) []rune {
+	return []rune(norm.NFC.String(string(key)))
+}
+
+// Normalizer applying Unicode compatibility composition (NFKC), e.g. "ﬁ"
+// becomes "fi" and "①" becomes "1".
+func NFKC(key []rune) []rune {
+	return []rune(norm.NFKC.String(string(key)))
+}
+
+// Normalizer removing accents and other nonspacing marks, e.g. "café"
+// becomes "cafe".
+func StripAccents(key []rune) []rune {
+	decomposed := []rune(norm.NFD.String(string(key)))
+	stripped := decomposed[:0]
+	for _, r := range decomposed {
+		if !unicode.Is(unicode.Mn, r) {
+			stripped = append(stripped, r)
+		}
+	}
+	return []rune(norm.NFC.String(string(stripped)))
+}
+
+// Returns a Normalizer applying the normalizers in order.
+func ChainNormalizers(normalizers ...Normalizer) Normalizer {
+	return func(key []rune) []rune {
+		for _, normalize := range normalizers {
+			key = normalize(key)
+		}
+		return key
+	}
+}
+
+// Represents a PrefixStoreRuneTrie that normalizes keys on Put and on every
+// lookup, while returning keys as they were originally put. When several
+// keys normalize to the same key, the one put first is retained.
+type NormalizedPrefixStoreRuneTrie struct {
+	trie       *PrefixStoreRuneTrie
+	normalizer Normalizer
+
+	// Original key by normalized key.
+	originals map[string][]rune
+}
+
+// Creates and returns reference to a new instance of
+// NormalizedPrefixStoreRuneTrie applying normalizer to keys.
+// maxKeySizeInRunes is the maximum size of the normalized key ([]rune) that
+// should be allowed to be added to the PrefixStore.
+func CreateNormalizedPrefixStoreRuneTrie(maxKeySizeInRunes int, normalizer Normalizer) *NormalizedPrefixStoreRuneTrie {
+	return &NormalizedPrefixStoreRuneTrie{
+		trie:       CreatePrefixStoreRuneTrie(maxKeySizeInRunes),
+		normalizer: normalizer,
+		originals:  make(map[string][]rune),
+	}
+}
+
+// Normalizes and adds the []rune key to the PrefixStore and returns if key
+// was succesfully added and any error encountered.
+// A non nil error is returned if the normalized key is longer than
+// maxKeySizeInRunes.
+func (t *NormalizedPrefixStoreRuneTrie) Put(key []rune) (bool, error) {
+	normalized := t.normalizer(key)
+	newlyAdded, err := t.trie.Put(normalized)
+	if err != nil {
+		return false, fmt.Errorf("normalized key: %v", err)
+	}
+	if newlyAdded {
+		original := make([]rune, len(key))
+		copy(original, key)
+		t.originals[string(normalized)] = original
+	}
+	return newlyAdded, nil
+}
+
+// Checks and returns if a key normalizing to the same key as given key is
+// present in the PrefixStore.
+func (t *NormalizedPrefixStoreRuneTrie) Exists(key []rune) bool {
+	return t.trie.Exists(t.normalizer(key))
+}
+
+// Does the prefix search on the PrefixStore for the normalized prefix and
+// returns a reference to list (*list.List) containings the original keys.
+// Each element of the list is []rune.
+func (t *NormalizedPrefixStoreRuneTrie) PrefixSearch(prefix []rune) *list.List {
+	entries := t.trie.PrefixSearch(t.normalizer(prefix))
+	for e := entries.Front(); e != nil; e = e.Next() {
+		e.Value = t.originals[string(e.Value.([]rune))]
+	}
+	return entries
+}
+
+// Removes the key normalizing to the same key as given key from the
+// PrefixStore and returns if it was present.
+func (t *NormalizedPrefixStoreRuneTrie) Delete(key []rune) bool {
+	normalized := t.normalizer(key)
+	if !t.trie.Delete(normalized) {
+		return false
+	}
+	delete(t.originals, string(normalized))
+	return true
+}
+
+// Returns the number of keys present in the PrefixStore.
+func (t *NormalizedPrefixStoreRuneTrie) Count() int {
+	return len(t.originals)
+}
+
+var _ RunePrefixStore = (*NormalizedPrefixStoreRuneTrie)(nil)
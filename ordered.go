@@ -0,0 +1,408 @@
+package tripod
+
+import (
+	"bytes"
+	"slices"
+)
+
+// The keys of a trie are ordered lexicographically, a key sorting right
+// before the keys it is a prefix of, hence a trie is also an ordered set:
+// the methods in this file navigate the children of the nodes in ascending
+// order of their keys.
+//
+// The methods returning a key return a copy of it, and a bool reporting if
+// there is such a key.
+
+// Returns the smallest key of the children of t greater than ch, if any.
+func (t *PrefixStoreByteTrie) nextChildKey(ch int) (int, bool) {
+	next, found := 0, false
+	for key := range t.children {
+		if key > ch && (!found || key < next) {
+			next, found = key, true
+		}
+	}
+	return next, found
+}
+
+// Returns the smallest and the greatest keys of the children of t, if any.
+func (t *PrefixStoreByteTrie) childKeyBounds() (int, int, bool) {
+	first, last, found := 0, 0, false
+	for key := range t.children {
+		if !found || key < first {
+			first = key
+		}
+		if !found || key > last {
+			last = key
+		}
+		found = true
+	}
+	return first, last, found
+}
+
+// Returns the greatest key of the children of t less than ch, if any.
+func (t *PrefixStoreByteTrie) prevChildKey(ch int) (int, bool) {
+	prev, found := 0, false
+	for key := range t.children {
+		if key < ch && (!found || key > prev) {
+			prev, found = key, true
+		}
+	}
+	return prev, found
+}
+
+// Returns the smallest key under t appended to buffer, if any.
+func (t *PrefixStoreByteTrie) minKey(buffer []byte) ([]byte, bool) {
+	current_node := t
+	for !current_node.isLast {
+		ch, _, found := current_node.childKeyBounds()
+		if !found {
+			return nil, false
+		}
+		buffer = append(buffer, byte(ch))
+		current_node = current_node.children[ch]
+	}
+	return buffer, true
+}
+
+// Returns the greatest key under t appended to buffer, if any.
+func (t *PrefixStoreByteTrie) maxKey(buffer []byte) ([]byte, bool) {
+	current_node := t
+	for len(current_node.children) > 0 {
+		_, ch, _ := current_node.childKeyBounds()
+		buffer = append(buffer, byte(ch))
+		current_node = current_node.children[ch]
+	}
+	if !current_node.isLast {
+		return nil, false
+	}
+	return buffer, true
+}
+
+// Returns the smallest key of the PrefixStore.
+func (t *PrefixStoreByteTrie) Min() ([]byte, bool) {
+	return t.minKey(nil)
+}
+
+// Returns the greatest key of the PrefixStore.
+func (t *PrefixStoreByteTrie) Max() ([]byte, bool) {
+	return t.maxKey(nil)
+}
+
+// Returns the smallest key under t, appended to buffer, greater than key, or
+// equal to it unless strict.
+func (t *PrefixStoreByteTrie) ceiling(key []byte, buffer []byte, strict bool) ([]byte, bool) {
+	if len(key) == 0 {
+		if t.isLast && !strict {
+			return buffer, true
+		}
+		// The keys under t, t excluded, are all greater than key.
+		if ch, _, found := t.childKeyBounds(); found {
+			return t.children[ch].minKey(append(buffer, byte(ch)))
+		}
+		return nil, false
+	}
+
+	// t is a proper prefix of key, hence it is smaller and never qualifies.
+	ch := int(key[0])
+	if child := t.children[ch]; child != nil {
+		if result, found := child.ceiling(key[1:], append(buffer, byte(ch)), strict); found {
+			return result, true
+		}
+	}
+	if next, found := t.nextChildKey(ch); found {
+		return t.children[next].minKey(append(buffer, byte(next)))
+	}
+	return nil, false
+}
+
+// Returns the greatest key under t, appended to buffer, less than key, or
+// equal to it unless strict.
+func (t *PrefixStoreByteTrie) floor(key []byte, buffer []byte, strict bool) ([]byte, bool) {
+	if len(key) == 0 {
+		// The keys under t, t excluded, are all greater than key.
+		if t.isLast && !strict {
+			return buffer, true
+		}
+		return nil, false
+	}
+
+	ch := int(key[0])
+	if child := t.children[ch]; child != nil {
+		if result, found := child.floor(key[1:], append(buffer, byte(ch)), strict); found {
+			return result, true
+		}
+	}
+	if prev, found := t.prevChildKey(ch); found {
+		return t.children[prev].maxKey(append(buffer, byte(prev)))
+	}
+	// t is a proper prefix of key, hence it is smaller.
+	if t.isLast {
+		return buffer, true
+	}
+	return nil, false
+}
+
+// Returns the greatest key of the PrefixStore less than or equal to key.
+func (t *PrefixStoreByteTrie) Floor(key []byte) ([]byte, bool) {
+	return t.floor(key, nil, false)
+}
+
+// Returns the smallest key of the PrefixStore greater than or equal to key.
+func (t *PrefixStoreByteTrie) Ceiling(key []byte) ([]byte, bool) {
+	return t.ceiling(key, nil, false)
+}
+
+// Returns the smallest key of the PrefixStore greater than key, which need
+// not be present.
+func (t *PrefixStoreByteTrie) Successor(key []byte) ([]byte, bool) {
+	return t.ceiling(key, nil, true)
+}
+
+// Returns the greatest key of the PrefixStore less than key, which need not
+// be present.
+func (t *PrefixStoreByteTrie) Predecessor(key []byte) ([]byte, bool) {
+	return t.floor(key, nil, true)
+}
+
+// Calls fn with every key of the PrefixStore greater than or equal to from
+// and less than to, in ascending order, until fn returns false. A nil to
+// leaves the range unbounded above. The key passed to fn is valid only for
+// the duration of the call.
+func (t *PrefixStoreByteTrie) Range(from, to []byte, fn func(key []byte) bool) {
+	t.walkRange(make([]byte, 0, t.maxKeySizeInBytes), from, true, func(key []byte) bool {
+		if to != nil && bytes.Compare(key, to) >= 0 {
+			return false
+		}
+		return fn(key)
+	})
+}
+
+// Does a Depth First Search traversal visiting children in ascending order,
+// and calls fn with every key under t, appended to buffer, that is not less
+// than the path to t followed by from, when bounded. Returns false as soon as
+// fn does, to stop the traversal.
+func (t *PrefixStoreByteTrie) walkRange(buffer []byte, from []byte, bounded bool, fn func(key []byte) bool) bool {
+	if bounded && len(from) == 0 {
+		bounded = false
+	}
+	if t.isLast && !bounded {
+		if !fn(buffer) {
+			return false
+		}
+	}
+
+	for _, ch := range t.sortedChildKeys() {
+		childBounded := false
+		if bounded {
+			if ch < int(from[0]) {
+				continue
+			}
+			childBounded = ch == int(from[0])
+		}
+		var rest []byte
+		if childBounded {
+			rest = from[1:]
+		}
+		if !t.children[ch].walkRange(append(buffer, byte(ch)), rest, childBounded, fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns the smallest key of the children of t greater than ch, if any.
+func (t *PrefixStoreRuneTrie) nextChildKey(ch rune) (rune, bool) {
+	next, found := rune(0), false
+	for key := range t.children {
+		if key > ch && (!found || key < next) {
+			next, found = key, true
+		}
+	}
+	return next, found
+}
+
+// Returns the smallest and the greatest keys of the children of t, if any.
+func (t *PrefixStoreRuneTrie) childKeyBounds() (rune, rune, bool) {
+	first, last, found := rune(0), rune(0), false
+	for key := range t.children {
+		if !found || key < first {
+			first = key
+		}
+		if !found || key > last {
+			last = key
+		}
+		found = true
+	}
+	return first, last, found
+}
+
+// Returns the greatest key of the children of t less than ch, if any.
+func (t *PrefixStoreRuneTrie) prevChildKey(ch rune) (rune, bool) {
+	prev, found := rune(0), false
+	for key := range t.children {
+		if key < ch && (!found || key > prev) {
+			prev, found = key, true
+		}
+	}
+	return prev, found
+}
+
+// Returns the smallest key under t appended to buffer, if any.
+func (t *PrefixStoreRuneTrie) minKey(buffer []rune) ([]rune, bool) {
+	current_node := t
+	for !current_node.isLast {
+		ch, _, found := current_node.childKeyBounds()
+		if !found {
+			return nil, false
+		}
+		buffer = append(buffer, ch)
+		current_node = current_node.children[ch]
+	}
+	return buffer, true
+}
+
+// Returns the greatest key under t appended to buffer, if any.
+func (t *PrefixStoreRuneTrie) maxKey(buffer []rune) ([]rune, bool) {
+	current_node := t
+	for len(current_node.children) > 0 {
+		_, ch, _ := current_node.childKeyBounds()
+		buffer = append(buffer, ch)
+		current_node = current_node.children[ch]
+	}
+	if !current_node.isLast {
+		return nil, false
+	}
+	return buffer, true
+}
+
+// Returns the smallest key of the PrefixStore.
+func (t *PrefixStoreRuneTrie) Min() ([]rune, bool) {
+	return t.minKey(nil)
+}
+
+// Returns the greatest key of the PrefixStore.
+func (t *PrefixStoreRuneTrie) Max() ([]rune, bool) {
+	return t.maxKey(nil)
+}
+
+// Returns the smallest key under t, appended to buffer, greater than key, or
+// equal to it unless strict.
+func (t *PrefixStoreRuneTrie) ceiling(key []rune, buffer []rune, strict bool) ([]rune, bool) {
+	if len(key) == 0 {
+		if t.isLast && !strict {
+			return buffer, true
+		}
+		// The keys under t, t excluded, are all greater than key.
+		if ch, _, found := t.childKeyBounds(); found {
+			return t.children[ch].minKey(append(buffer, ch))
+		}
+		return nil, false
+	}
+
+	// t is a proper prefix of key, hence it is smaller and never qualifies.
+	ch := key[0]
+	if child := t.children[ch]; child != nil {
+		if result, found := child.ceiling(key[1:], append(buffer, ch), strict); found {
+			return result, true
+		}
+	}
+	if next, found := t.nextChildKey(ch); found {
+		return t.children[next].minKey(append(buffer, next))
+	}
+	return nil, false
+}
+
+// Returns the greatest key under t, appended to buffer, less than key, or
+// equal to it unless strict.
+func (t *PrefixStoreRuneTrie) floor(key []rune, buffer []rune, strict bool) ([]rune, bool) {
+	if len(key) == 0 {
+		// The keys under t, t excluded, are all greater than key.
+		if t.isLast && !strict {
+			return buffer, true
+		}
+		return nil, false
+	}
+
+	ch := key[0]
+	if child := t.children[ch]; child != nil {
+		if result, found := child.floor(key[1:], append(buffer, ch), strict); found {
+			return result, true
+		}
+	}
+	if prev, found := t.prevChildKey(ch); found {
+		return t.children[prev].maxKey(append(buffer, prev))
+	}
+	// t is a proper prefix of key, hence it is smaller.
+	if t.isLast {
+		return buffer, true
+	}
+	return nil, false
+}
+
+// Returns the greatest key of the PrefixStore less than or equal to key.
+func (t *PrefixStoreRuneTrie) Floor(key []rune) ([]rune, bool) {
+	return t.floor(key, nil, false)
+}
+
+// Returns the smallest key of the PrefixStore greater than or equal to key.
+func (t *PrefixStoreRuneTrie) Ceiling(key []rune) ([]rune, bool) {
+	return t.ceiling(key, nil, false)
+}
+
+// Returns the smallest key of the PrefixStore greater than key, which need
+// not be present.
+func (t *PrefixStoreRuneTrie) Successor(key []rune) ([]rune, bool) {
+	return t.ceiling(key, nil, true)
+}
+
+// Returns the greatest key of the PrefixStore less than key, which need not
+// be present.
+func (t *PrefixStoreRuneTrie) Predecessor(key []rune) ([]rune, bool) {
+	return t.floor(key, nil, true)
+}
+
+// Calls fn with every key of the PrefixStore greater than or equal to from
+// and less than to, in ascending order, until fn returns false. A nil to
+// leaves the range unbounded above. The key passed to fn is valid only for
+// the duration of the call.
+func (t *PrefixStoreRuneTrie) Range(from, to []rune, fn func(key []rune) bool) {
+	t.walkRange(make([]rune, 0, t.maxKeySizeInRunes), from, true, func(key []rune) bool {
+		if to != nil && slices.Compare(key, to) >= 0 {
+			return false
+		}
+		return fn(key)
+	})
+}
+
+// Does a Depth First Search traversal visiting children in ascending order,
+// and calls fn with every key under t, appended to buffer, that is not less
+// than the path to t followed by from, when bounded. Returns false as soon as
+// fn does, to stop the traversal.
+func (t *PrefixStoreRuneTrie) walkRange(buffer []rune, from []rune, bounded bool, fn func(key []rune) bool) bool {
+	if bounded && len(from) == 0 {
+		bounded = false
+	}
+	if t.isLast && !bounded {
+		if !fn(buffer) {
+			return false
+		}
+	}
+
+	for _, ch := range t.sortedChildKeys() {
+		childBounded := false
+		if bounded {
+			if ch < from[0] {
+				continue
+			}
+			childBounded = ch == from[0]
+		}
+		var rest []rune
+		if childBounded {
+			rest = from[1:]
+		}
+		if !t.children[ch].walkRange(append(buffer, ch), rest, childBounded, fn) {
+			return false
+		}
+	}
+	return true
+}
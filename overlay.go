@@ -0,0 +1,114 @@
+package tripod
+
+import (
+	"container/list"
+)
+
+// Represents a BytePrefixStore stacking a small mutable layer on a shared
+// read-only base store: keys put are added to the layer, and deleting a key
+// of the base hides it behind a tombstone, while the base is left untouched.
+// Lookups merge the layer and the base without copying the base, hence many
+// overlays, one per tenant for instance, can be served off a single large
+// base store.
+// The base must not be modified while overlays are stacked on it. Overlays
+// only read it, hence they can share it across goroutines, but an
+// OverlayPrefixStore itself is not safe for concurrent use.
+type OverlayPrefixStore struct {
+	base BytePrefixStore
+
+	// Keys added by the layer, none of which is in the base, and keys of the
+	// base hidden by the layer.
+	additions  *PrefixStoreByteTrie
+	tombstones *PrefixStoreByteTrie
+}
+
+// Creates and returns reference to a new instance of OverlayPrefixStore over
+// base, initially holding the keys of base.
+// maxKeySizeInBytes is the maximum size of the key ([]byte) that should be
+// allowed to be added to the layer. When tried to put key of length more than
+// maxKeySizeInBytes, the method will return the error. Any key of the base can
+// be deleted, whatever its length.
+func CreateOverlayPrefixStore(base BytePrefixStore, maxKeySizeInBytes int) *OverlayPrefixStore {
+	return &OverlayPrefixStore{
+		base:       base,
+		additions:  CreatePrefixStoreByteTrie(maxKeySizeInBytes),
+		tombstones: CreatePrefixStoreByteTrie(maxKeySizeInBytes),
+	}
+}
+
+// Adds the key to the store and returns if key was succesfully added and any
+// error encountered. A key of the base that was deleted is made visible again
+// by removing its tombstone.
+// A non nil error is returned if len(key) > maxKeySizeInBytes
+func (s *OverlayPrefixStore) Put(key []byte) (bool, error) {
+	if s.tombstones.Delete(key) {
+		return true, nil
+	}
+	if s.base.Exists(key) {
+		return false, nil
+	}
+	return s.additions.Put(key)
+}
+
+// Checks and returns if given key is present in the store, i.e. in the layer,
+// or in the base and not deleted.
+func (s *OverlayPrefixStore) Exists(key []byte) bool {
+	if s.additions.Exists(key) {
+		return true
+	}
+	return s.base.Exists(key) && !s.tombstones.Exists(key)
+}
+
+// Removes the key from the store and returns if the key was present. A key of
+// the base is hidden by a tombstone.
+func (s *OverlayPrefixStore) Delete(key []byte) bool {
+	if s.additions.Delete(key) {
+		return true
+	}
+	if !s.base.Exists(key) {
+		return false
+	}
+	// The tombstones only ever hold keys of the base, hence they grow to fit
+	// the keys of the base longer than the maximum key size of the layer.
+	if len(key) > s.tombstones.maxKeySizeInBytes {
+		s.tombstones.maxKeySizeInBytes = len(key)
+	}
+	newlyAdded, _ := s.tombstones.Put(key)
+	return newlyAdded
+}
+
+// Returns the number of keys present in the store.
+func (s *OverlayPrefixStore) Count() int {
+	return s.base.Count() - s.tombstones.Count() + s.additions.Count()
+}
+
+// Does the prefix search on the layer and on the base, and returns a
+// reference to list (*list.List) containing the keys present in the store for
+// the given prefix: the keys added by the layer, followed by the keys of the
+// base that are not deleted. Each element of the list is []byte.
+func (s *OverlayPrefixStore) PrefixSearch(prefix []byte) *list.List {
+	entries := s.additions.PrefixSearch(prefix)
+
+	baseEntries := s.base.PrefixSearch(prefix)
+	if hidden := s.tombstones.get(prefix); hidden == nil || hidden.count == 0 {
+		// No key under prefix is deleted, hence the base keys are all
+		// present, and none of them is in the layer.
+		entries.PushBackList(baseEntries)
+		return entries
+	}
+	for e := baseEntries.Front(); e != nil; e = e.Next() {
+		if !s.tombstones.Exists(e.Value.([]byte)) {
+			entries.PushBack(e.Value)
+		}
+	}
+	return entries
+}
+
+// Returns the keys added by the layer, and the keys of the base it deletes,
+// so that the customizations of a tenant can be persisted on their own. The
+// returned tries must not be modified.
+func (s *OverlayPrefixStore) Layer() (additions, tombstones *PrefixStoreByteTrie) {
+	return s.additions, s.tombstones
+}
+
+var _ BytePrefixStore = (*OverlayPrefixStore)(nil)
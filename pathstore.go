@@ -0,0 +1,293 @@
+package tripod
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Represents a store of hierarchical keys, like file paths, URL paths or
+// dotted config keys, which uses an in-memory trie whose edges are the
+// segments of the keys between separators. Hence searches always match whole
+// segments: "/usr/lo" does not match "/usr/local".
+// Segments are kept verbatim, including empty ones, so that keys are
+// returned exactly as they were put: "/usr/local" is made of the segments
+// "", "usr" and "local", and differs from "usr/local" and "/usr/local/".
+type PathStore struct {
+	root                 *pathNode
+	separator            string
+	maxKeySizeInSegments int
+
+	// Number of keys present, maintained by Put and Delete.
+	count int
+}
+
+// A node of a trie whose edges are path segments.
+type pathNode struct {
+	isLast   bool
+	children map[string]*pathNode
+}
+
+// Represents a stored key matching a pattern, along with the segments bound
+// to the parameters of the pattern, by name.
+type PathMatch struct {
+	Key    string
+	Params map[string]string
+}
+
+// Creates and returns reference to a new instance of PathStore, splitting
+// keys on separator.
+// maxKeySizeInSegments is the maximum number of segments in the key that
+// should be allowed to be added to the store. When tried to put key with more
+// than maxKeySizeInSegments segments, the method will return the error.
+// It panics if separator is empty, as keys could not be split on it.
+func CreatePathStore(separator string, maxKeySizeInSegments int) *PathStore {
+	if separator == "" {
+		panic("tripod: separator of PathStore should not be empty")
+	}
+	return &PathStore{
+		root:                 &pathNode{},
+		separator:            separator,
+		maxKeySizeInSegments: maxKeySizeInSegments,
+	}
+}
+
+// Returns the child of n for the segment, creating it if needed.
+func (n *pathNode) child(segment string) *pathNode {
+	child := n.children[segment]
+	if child == nil {
+		child = &pathNode{}
+		if n.children == nil {
+			n.children = make(map[string]*pathNode)
+		}
+		// The segment is cloned so that the edge does not retain the whole
+		// key.
+		n.children[strings.Clone(segment)] = child
+	}
+	return child
+}
+
+// Returns the children segments of n in ascending order.
+func (n *pathNode) sortedChildKeys() []string {
+	segments := make([]string, 0, len(n.children))
+	for segment := range n.children {
+		segments = append(segments, segment)
+	}
+	sort.Strings(segments)
+	return segments
+}
+
+// Adds the key to the store and returns if key was succesfully added and any
+// error encountered.
+// A non nil error is returned if key has more than maxKeySizeInSegments
+// segments.
+func (s *PathStore) Put(key string) (bool, error) {
+	if size := strings.Count(key, s.separator) + 1; size > s.maxKeySizeInSegments {
+		return false, fmt.Errorf("max size of key should be %d (%d > %d)",
+			s.maxKeySizeInSegments, size, s.maxKeySizeInSegments)
+	}
+	if len(key) == 0 {
+		return false, nil
+	}
+
+	current_node := s.root
+	for rest, more := key, true; more; {
+		var segment string
+		segment, rest, more = strings.Cut(rest, s.separator)
+		current_node = current_node.child(segment)
+	}
+
+	if current_node.isLast {
+		return false, nil
+	}
+	current_node.isLast = true
+	s.count++
+	return true, nil
+}
+
+// Checks and returns if given key is present in the store.
+func (s *PathStore) Exists(key string) bool {
+	current_node := s.get(key)
+	return current_node != nil && current_node.isLast
+}
+
+// For a given path, this method returns a reference to the node that ends at
+// it, or nil.
+func (s *PathStore) get(path string) *pathNode {
+	if len(path) == 0 {
+		return s.root
+	}
+
+	current_node := s.root
+	depth := 0
+	for rest, more := path, true; more; depth++ {
+		if depth == s.maxKeySizeInSegments {
+			// Shorting the lookup, since Put method does not allow to put
+			// key with more than maxKeySizeInSegments segments.
+			return nil
+		}
+		var segment string
+		segment, rest, more = strings.Cut(rest, s.separator)
+		current_node = current_node.children[segment]
+		if current_node == nil {
+			return nil
+		}
+	}
+	return current_node
+}
+
+// Removes the key from the store and returns if the key was present. Nodes
+// that are left with no key under them are pruned.
+func (s *PathStore) Delete(key string) bool {
+	if len(key) == 0 {
+		return false
+	}
+	deleted, _ := s.root.delete(key, s.separator)
+	if deleted {
+		s.count--
+	}
+	return deleted
+}
+
+// Recursively removes the key made of the segments of path under n and
+// returns if the key was present and if n is left with no key under it, so
+// that the parent can prune it.
+func (n *pathNode) delete(path string, separator string) (bool, bool) {
+	segment, rest, more := strings.Cut(path, separator)
+	child := n.children[segment]
+	if child == nil {
+		return false, false
+	}
+
+	var deleted, prune bool
+	if more {
+		deleted, prune = child.delete(rest, separator)
+	} else if child.isLast {
+		child.isLast = false
+		deleted, prune = true, len(child.children) == 0
+	}
+	if prune {
+		delete(n.children, segment)
+	}
+	return deleted, prune && !n.isLast && len(n.children) == 0
+}
+
+// Returns the number of keys present in the store.
+func (s *PathStore) Count() int {
+	return s.count
+}
+
+// For a given path to list, this method returns a reference to the node that
+// ends at it, or nil, and the path without its trailing separator, if any, so
+// that "/usr/" lists as "/usr", and "/" lists the keys starting with "/".
+func (s *PathStore) dir(path string) (*pathNode, string) {
+	if path == s.separator {
+		return s.root.children[""], ""
+	}
+	path = strings.TrimSuffix(path, s.separator)
+	return s.get(path), path
+}
+
+// Returns, in ascending order, the segments directly under path, like `ls`
+// does for a directory. A segment is listed whether it ends a key or only
+// leads to longer keys. An empty path lists the first segments of the keys.
+func (s *PathStore) ListChildren(path string) []string {
+	current_node, _ := s.dir(path)
+	if current_node == nil {
+		return nil
+	}
+	return current_node.sortedChildKeys()
+}
+
+// Returns every key having path as its leading segments, including path
+// itself if it is a key, in ascending order of segments. An empty path lists
+// all the keys.
+func (s *PathStore) ListSubtree(path string) []string {
+	current_node, path := s.dir(path)
+	if current_node == nil {
+		return nil
+	}
+
+	var entries []string
+	if current_node == s.root {
+		for _, segment := range current_node.sortedChildKeys() {
+			current_node.children[segment].walk([]byte(segment), s.separator, func(key string) {
+				entries = append(entries, key)
+			})
+		}
+		return entries
+	}
+	current_node.walk([]byte(path), s.separator, func(key string) {
+		entries = append(entries, key)
+	})
+	return entries
+}
+
+// Does a Depth First Search traversal visiting children in ascending order,
+// and calls fn with every key under n, with buffer holding the path to n.
+func (n *pathNode) walk(buffer []byte, separator string, fn func(key string)) {
+	if n.isLast {
+		fn(string(buffer))
+	}
+	for _, segment := range n.sortedChildKeys() {
+		next := append(append(buffer, separator...), segment...)
+		n.children[segment].walk(next, separator, fn)
+	}
+}
+
+// Returns every key matching pattern, in ascending order of segments. A
+// segment of the pattern of the form ":name" is a parameter, which matches
+// any single segment and binds it to name in the Params of the match; every
+// other segment must match as is. For example "/users/:id/posts" matches
+// "/users/42/posts" with Params {"id": "42"}, but not "/users/42/posts/7".
+func (s *PathStore) Match(pattern string) []PathMatch {
+	if len(pattern) == 0 {
+		return nil
+	}
+	segments := strings.Split(pattern, s.separator)
+	if len(segments) > s.maxKeySizeInSegments {
+		return nil
+	}
+
+	var entries []PathMatch
+	var params []string // name and segment pairs
+	var match func(n *pathNode, i int, buffer []byte)
+	match = func(n *pathNode, i int, buffer []byte) {
+		if i == len(segments) {
+			if n.isLast {
+				entries = append(entries, PathMatch{Key: string(buffer), Params: paramsMap(params)})
+			}
+			return
+		}
+		if i > 0 {
+			buffer = append(buffer, s.separator...)
+		}
+
+		segment := segments[i]
+		if len(segment) > 1 && segment[0] == ':' {
+			for _, child := range n.sortedChildKeys() {
+				params = append(params, segment[1:], child)
+				match(n.children[child], i+1, append(buffer, child...))
+				params = params[:len(params)-2]
+			}
+			return
+		}
+		if child := n.children[segment]; child != nil {
+			match(child, i+1, append(buffer, segment...))
+		}
+	}
+	match(s.root, 0, make([]byte, 0, len(pattern)))
+	return entries
+}
+
+// Returns the name and value pairs as a map, or nil if there are none.
+func paramsMap(pairs []string) map[string]string {
+	if len(pairs) == 0 {
+		return nil
+	}
+	params := make(map[string]string, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		params[pairs[i]] = pairs[i+1]
+	}
+	return params
+}
@@ -0,0 +1,32 @@
+package tripod
+
+import (
+	"container/list"
+)
+
+// Represents a PrefixStore keyed by []byte. It is implemented by
+// PrefixStoreByteTrie as well as by the stores served remotely, so that code
+// can switch between local and remote stores without changes.
+type BytePrefixStore interface {
+	Put(key []byte) (bool, error)
+	Exists(key []byte) bool
+	PrefixSearch(prefix []byte) *list.List
+	Delete(key []byte) bool
+	Count() int
+}
+
+// Represents a PrefixStore keyed by []rune. It is implemented by
+// PrefixStoreRuneTrie as well as by the stores served remotely, so that code
+// can switch between local and remote stores without changes.
+type RunePrefixStore interface {
+	Put(key []rune) (bool, error)
+	Exists(key []rune) bool
+	PrefixSearch(prefix []rune) *list.List
+	Delete(key []rune) bool
+	Count() int
+}
+
+var (
+	_ BytePrefixStore = (*PrefixStoreByteTrie)(nil)
+	_ RunePrefixStore = (*PrefixStoreRuneTrie)(nil)
+)
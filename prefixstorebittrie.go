@@ -0,0 +1,264 @@
+package tripod
+
+import (
+	"fmt"
+	"net/netip"
+)
+
+// Represents the PrefixStore which uses an in-memory binary trie to store IP
+// prefixes (CIDR blocks) with bit granularity, and efficiently return the
+// longest stored prefix matching an address.
+// IPv4 and IPv6 prefixes are stored in separate tries; an IPv4-mapped IPv6
+// prefix or address is an IPv6 one.
+type PrefixStoreBitTrie struct {
+	v4 *bitTrieNode
+	v6 *bitTrieNode
+
+	// Number of prefixes present, of both families, maintained by Put and
+	// Delete.
+	count int
+}
+
+// A node of the binary trie, at the depth of the number of bits of the
+// prefix it ends.
+type bitTrieNode struct {
+	isLast   bool
+	children [2]*bitTrieNode
+}
+
+// Creates and returns reference to a new instance of PrefixStoreBitTrie.
+func CreatePrefixStoreBitTrie() *PrefixStoreBitTrie {
+	return &PrefixStoreBitTrie{
+		v4: &bitTrieNode{},
+		v6: &bitTrieNode{},
+	}
+}
+
+// Returns the root of the trie storing the prefixes of the address family of
+// addr.
+func (t *PrefixStoreBitTrie) root(addr netip.Addr) *bitTrieNode {
+	if addr.Is4() {
+		return t.v4
+	}
+	return t.v6
+}
+
+// Returns the bit of the address at index i, the most significant bit being
+// at index 0.
+func addrBit(addr *[16]byte, i int) int {
+	return int(addr[i/8]>>(7-i%8)) & 1
+}
+
+// Returns the bytes of the address, left aligned, so that bits are indexed
+// the same way for both families.
+func addrBytes(addr netip.Addr) [16]byte {
+	var b [16]byte
+	if addr.Is4() {
+		a4 := addr.As4()
+		copy(b[:], a4[:])
+		return b
+	}
+	return addr.As16()
+}
+
+// Returns the prefix of the given family made of the first bits of addr.
+func prefixFromBytes(is4 bool, addr [16]byte, bits int) netip.Prefix {
+	if is4 {
+		return netip.PrefixFrom(netip.AddrFrom4([4]byte(addr[:4])), bits)
+	}
+	return netip.PrefixFrom(netip.AddrFrom16(addr), bits)
+}
+
+// Adds the prefix to the PrefixStore and returns if prefix was succesfully
+// added and any error encountered. The host bits of the prefix are ignored,
+// i.e. 10.1.2.3/8 is stored as 10.0.0.0/8.
+// A non nil error is returned if prefix is not valid.
+func (t *PrefixStoreBitTrie) Put(prefix netip.Prefix) (bool, error) {
+	if !prefix.IsValid() {
+		return false, fmt.Errorf("invalid prefix %s", prefix)
+	}
+
+	addr := addrBytes(prefix.Addr())
+	current_node := t.root(prefix.Addr())
+	for i := 0; i < prefix.Bits(); i++ {
+		bit := addrBit(&addr, i)
+		child := current_node.children[bit]
+		if child == nil {
+			child = &bitTrieNode{}
+			current_node.children[bit] = child
+		}
+		current_node = child
+	}
+
+	// Unlike the other PrefixStores, the empty key is a valid prefix here:
+	// 0.0.0.0/0 or ::/0, the default route.
+	if current_node.isLast {
+		return false, nil
+	}
+	current_node.isLast = true
+	t.count++
+	return true, nil
+}
+
+// Checks and returns if given prefix is present in the PrefixStore. The host
+// bits of the prefix are ignored.
+func (t *PrefixStoreBitTrie) Exists(prefix netip.Prefix) bool {
+	if !prefix.IsValid() {
+		return false
+	}
+
+	addr := addrBytes(prefix.Addr())
+	current_node := t.root(prefix.Addr())
+	for i := 0; i < prefix.Bits(); i++ {
+		current_node = current_node.children[addrBit(&addr, i)]
+		if current_node == nil {
+			return false
+		}
+	}
+	return current_node.isLast
+}
+
+// Removes the prefix from the PrefixStore and returns if the prefix was
+// present. Nodes that are left with no prefix under them are pruned.
+func (t *PrefixStoreBitTrie) Delete(prefix netip.Prefix) bool {
+	if !prefix.IsValid() {
+		return false
+	}
+	addr := addrBytes(prefix.Addr())
+	deleted, _ := t.root(prefix.Addr()).delete(&addr, 0, prefix.Bits())
+	if deleted {
+		t.count--
+	}
+	return deleted
+}
+
+// Recursively removes the prefix made of the first bits of addr under n, at
+// depth, and returns if the prefix was present and if n is left with no
+// prefix under it, so that the parent can prune it.
+func (n *bitTrieNode) delete(addr *[16]byte, depth, bits int) (bool, bool) {
+	if depth == bits {
+		if !n.isLast {
+			return false, false
+		}
+		n.isLast = false
+		return true, n.children[0] == nil && n.children[1] == nil
+	}
+
+	bit := addrBit(addr, depth)
+	child := n.children[bit]
+	if child == nil {
+		return false, false
+	}
+	deleted, prune := child.delete(addr, depth+1, bits)
+	if prune {
+		n.children[bit] = nil
+	}
+	return deleted, prune && !n.isLast && n.children[0] == nil && n.children[1] == nil
+}
+
+// Returns the number of prefixes present in the PrefixStore.
+func (t *PrefixStoreBitTrie) Count() int {
+	return t.count
+}
+
+// Returns the longest stored prefix containing addr, and if there is one.
+func (t *PrefixStoreBitTrie) LongestMatch(addr netip.Addr) (netip.Prefix, bool) {
+	if !addr.IsValid() {
+		return netip.Prefix{}, false
+	}
+
+	// Prefixes do not have zones, hence fe80::1%eth0 matches as fe80::1.
+	addr = addr.WithZone("")
+	bytes := addrBytes(addr)
+	current_node := t.root(addr)
+	longest := -1
+	for i := 0; ; i++ {
+		if current_node.isLast {
+			longest = i
+		}
+		if i == addr.BitLen() {
+			break
+		}
+		current_node = current_node.children[addrBit(&bytes, i)]
+		if current_node == nil {
+			break
+		}
+	}
+
+	if longest < 0 {
+		return netip.Prefix{}, false
+	}
+	return netip.PrefixFrom(addr, longest).Masked(), true
+}
+
+// Returns the stored prefixes containing prefix, i.e. the ones it is a
+// sub-block of, including prefix itself if stored, from the shortest to the
+// longest.
+func (t *PrefixStoreBitTrie) Covering(prefix netip.Prefix) []netip.Prefix {
+	if !prefix.IsValid() {
+		return nil
+	}
+
+	var entries []netip.Prefix
+	addr := addrBytes(prefix.Addr())
+	current_node := t.root(prefix.Addr())
+	for i := 0; ; i++ {
+		if current_node.isLast {
+			entries = append(entries, netip.PrefixFrom(prefix.Addr(), i).Masked())
+		}
+		if i == prefix.Bits() {
+			break
+		}
+		current_node = current_node.children[addrBit(&addr, i)]
+		if current_node == nil {
+			break
+		}
+	}
+	return entries
+}
+
+// Returns the stored prefixes contained in prefix, i.e. its sub-blocks,
+// including prefix itself if stored, in ascending order of address and then
+// of length.
+func (t *PrefixStoreBitTrie) Covered(prefix netip.Prefix) []netip.Prefix {
+	if !prefix.IsValid() {
+		return nil
+	}
+
+	prefix = prefix.Masked()
+	addr := addrBytes(prefix.Addr())
+	current_node := t.root(prefix.Addr())
+	for i := 0; i < prefix.Bits(); i++ {
+		current_node = current_node.children[addrBit(&addr, i)]
+		if current_node == nil {
+			return nil
+		}
+	}
+
+	var entries []netip.Prefix
+	current_node.walk(prefix.Addr().Is4(), &addr, prefix.Bits(), func(p netip.Prefix) {
+		entries = append(entries, p)
+	})
+	return entries
+}
+
+// Does a Depth First Search traversal, visiting the 0 child first, and calls
+// fn with every prefix under n. addr holds the bits of the path to n, at
+// depth; the bits below are set while visiting the children.
+func (n *bitTrieNode) walk(is4 bool, addr *[16]byte, depth int, fn func(p netip.Prefix)) {
+	if n.isLast {
+		fn(prefixFromBytes(is4, *addr, depth))
+	}
+
+	mask := byte(1) << (7 - depth%8)
+	for bit, child := range n.children {
+		if child == nil {
+			continue
+		}
+		if bit == 1 {
+			addr[depth/8] |= mask
+		}
+		child.walk(is4, addr, depth+1, fn)
+		addr[depth/8] &^= mask
+	}
+}
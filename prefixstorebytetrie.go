@@ -4,6 +4,7 @@ package tripod
 import (
 	"container/list"
 	"fmt"
+	"sort"
 )
 
 // Represents the PrefixStore which uses an in-memory trie data-structure to
@@ -13,6 +14,14 @@ type PrefixStoreByteTrie struct {
 	isLast            bool
 	children          map[int]*PrefixStoreByteTrie
 	maxKeySizeInBytes int
+
+	// Number of keys under the node, itself included, maintained by every
+	// method adding or removing keys.
+	count int
+
+	// Number of nodes under the node, itself included, maintained along
+	// with count.
+	nodes int
 }
 
 // Creates and returns reference to a new instance of PrefixStoreByteTrie.
@@ -23,6 +32,7 @@ func CreatePrefixStoreByteTrie(maxKeySizeInBytes int) *PrefixStoreByteTrie {
 	return &PrefixStoreByteTrie{
 		children:          make(map[int]*PrefixStoreByteTrie),
 		maxKeySizeInBytes: maxKeySizeInBytes,
+		nodes:             1,
 	}
 }
 
@@ -36,10 +46,17 @@ func (t *PrefixStoreByteTrie) Put(key []byte) (bool, error) {
 	}
 
 	current_node := t
+	created := 0
 	for _, b := range key {
 		child := current_node.children[int(b)]
 		if child == nil {
+			created++
 			child = CreatePrefixStoreByteTrie(t.maxKeySizeInBytes)
+			if current_node.children == nil {
+				// Leaf nodes created by BuildPrefixStoreByteTrieFromSorted
+				// do not have children map allocated.
+				current_node.children = make(map[int]*PrefixStoreByteTrie)
+			}
 			current_node.children[int(b)] = child
 		}
 		current_node = child
@@ -51,9 +68,23 @@ func (t *PrefixStoreByteTrie) Put(key []byte) (bool, error) {
 		return false, nil
 	}
 
-	newlyAdded := current_node.isLast == false
+	if current_node.isLast {
+		return false, nil
+	}
 	current_node.isLast = true
-	return newlyAdded, nil
+
+	// The key is new, hence every node on its path has one more key under
+	// it, and the created nodes, being the last ones of the path, under the
+	// nodes above them.
+	current_node = t
+	current_node.count++
+	current_node.nodes += created
+	for i, b := range key {
+		current_node = current_node.children[int(b)]
+		current_node.count++
+		current_node.nodes += min(created, len(key)-i-1)
+	}
+	return true, nil
 }
 
 // Checks and returns if given key is present in the PrefixStore
@@ -75,6 +106,51 @@ func (t *PrefixStoreByteTrie) Exists(key []byte) bool {
 	return current_node.isLast
 }
 
+// Removes the key from the PrefixStore and returns if the key was present.
+// Nodes that are left with no key under them are pruned.
+func (t *PrefixStoreByteTrie) Delete(key []byte) bool {
+	if len(key) == 0 || len(key) > t.maxKeySizeInBytes {
+		return false
+	}
+	deleted, _ := t.delete(key)
+	return deleted
+}
+
+// Recursively removes the key under t and returns if the key was present and
+// if t is left with no key under it, so that the parent can prune it.
+func (t *PrefixStoreByteTrie) delete(key []byte) (bool, bool) {
+	if len(key) == 0 {
+		if !t.isLast {
+			return false, false
+		}
+		t.isLast = false
+		t.count--
+		return true, len(t.children) == 0
+	}
+
+	child := t.children[int(key[0])]
+	if child == nil {
+		return false, false
+	}
+	nodes := child.nodes
+	deleted, prune := child.delete(key[1:])
+	if deleted {
+		t.count--
+	}
+	if prune {
+		delete(t.children, int(key[0]))
+		t.nodes -= nodes
+	} else {
+		t.nodes -= nodes - child.nodes
+	}
+	return deleted, prune && !t.isLast && len(t.children) == 0
+}
+
+// Returns the number of keys present in the PrefixStore.
+func (t *PrefixStoreByteTrie) Count() int {
+	return t.count
+}
+
 // For a given instance of PrefixStore t, this method returns a reference to
 // subPrefixStore that ends at the key.
 func (t *PrefixStoreByteTrie) get(key []byte) *PrefixStoreByteTrie {
@@ -131,6 +207,28 @@ func _dfs(t *PrefixStoreByteTrie, prefix []byte, buffer []byte, entries *list.Li
 	}
 }
 
+// Returns the keys of the children of t in ascending order.
+func (t *PrefixStoreByteTrie) sortedChildKeys() []int {
+	keys := make([]int, 0, len(t.children))
+	for ch := range t.children {
+		keys = append(keys, ch)
+	}
+	sort.Ints(keys)
+	return keys
+}
+
+// Does a Depth First Search traversal on the PrefixStoreByteTrie visiting
+// children in ascending order, and calls fn with every key under t appended
+// to buffer. The key passed to fn is valid only for the duration of the call.
+func (t *PrefixStoreByteTrie) walkSorted(buffer []byte, fn func(key []byte)) {
+	if t.isLast {
+		fn(buffer)
+	}
+	for _, ch := range t.sortedChildKeys() {
+		t.children[ch].walkSorted(append(buffer, byte(ch)), fn)
+	}
+}
+
 // Does the prefix search on the PrefixStore and returns a reference to
 // list (*list.List) containings all entries from the store for the given
 // prefix. Each element of the list is []byte.
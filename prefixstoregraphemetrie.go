@@ -0,0 +1,198 @@
+package tripod
+
+import (
+	"fmt"
+	"github.com/rivo/uniseg"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Represents the PrefixStore which uses an in-memory trie data-structure to
+// store keys and efficiently return them when searched by prefix.
+// PrefixStoreGraphemeTrie segments keys into extended grapheme clusters
+// (UAX #29), i.e. user-perceived characters, and uses the clusters as edges.
+// Hence a prefix always matches whole visible characters: "e" does not match
+// "é" written as "e" and a combining accent, and half of an emoji ZWJ
+// sequence does not match the sequence.
+type PrefixStoreGraphemeTrie struct {
+	isLast               bool
+	children             map[string]*PrefixStoreGraphemeTrie
+	maxKeySizeInClusters int
+
+	// Number of keys under the node, itself included, maintained by every
+	// method adding or removing keys.
+	count int
+}
+
+// Creates and returns reference to a new instance of PrefixStoreGraphemeTrie.
+// maxKeySizeInClusters is the maximum number of grapheme clusters in the key
+// that should be allowed to be added to the PrefixStore. When tried to put
+// key of length more than maxKeySizeInClusters, the method will return the
+// error.
+func CreatePrefixStoreGraphemeTrie(maxKeySizeInClusters int) *PrefixStoreGraphemeTrie {
+	return &PrefixStoreGraphemeTrie{
+		children:             make(map[string]*PrefixStoreGraphemeTrie),
+		maxKeySizeInClusters: maxKeySizeInClusters,
+	}
+}
+
+// Adds the string key to the PrefixStore and returns if key was succesfully
+// added and any error encountered.
+// A non nil error is returned if key is not valid UTF-8 or if it has more
+// than maxKeySizeInClusters grapheme clusters.
+func (t *PrefixStoreGraphemeTrie) Put(key string) (bool, error) {
+	if !utf8.ValidString(key) {
+		return false, fmt.Errorf("key should be valid UTF-8")
+	}
+	if size := uniseg.GraphemeClusterCount(key); size > t.maxKeySizeInClusters {
+		return false, fmt.Errorf("max size of key should be %d (%d > %d)",
+			t.maxKeySizeInClusters, size, t.maxKeySizeInClusters)
+	}
+
+	current_node := t
+	state := -1
+	for rest := key; len(rest) > 0; {
+		var cluster string
+		cluster, rest, _, state = uniseg.FirstGraphemeClusterInString(rest, state)
+		child := current_node.children[cluster]
+		if child == nil {
+			child = CreatePrefixStoreGraphemeTrie(t.maxKeySizeInClusters)
+			// The cluster is cloned so that the edge does not retain the
+			// whole key.
+			current_node.children[strings.Clone(cluster)] = child
+		}
+		current_node = child
+	}
+
+	// If key is empty then current_node == t, and since nothing was added
+	// hence returning false.
+	if current_node == t {
+		return false, nil
+	}
+
+	if current_node.isLast {
+		return false, nil
+	}
+	current_node.isLast = true
+
+	// The key is new, hence every node on its path has one more key under
+	// it.
+	current_node = t
+	current_node.count++
+	state = -1
+	for rest := key; len(rest) > 0; {
+		var cluster string
+		cluster, rest, _, state = uniseg.FirstGraphemeClusterInString(rest, state)
+		current_node = current_node.children[cluster]
+		current_node.count++
+	}
+	return true, nil
+}
+
+// Checks and returns if given key is present in the PrefixStore
+func (t *PrefixStoreGraphemeTrie) Exists(key string) bool {
+	current_node := t.get(key)
+	return current_node != nil && current_node.isLast
+}
+
+// For a given instance of PrefixStore t, this method returns a reference to
+// subPrefixStore that ends at the key.
+func (t *PrefixStoreGraphemeTrie) get(key string) *PrefixStoreGraphemeTrie {
+	current_node := t
+	state := -1
+	depth := 0
+	for rest := key; len(rest) > 0; depth++ {
+		if depth == t.maxKeySizeInClusters {
+			// Shorting the lookup, since Put method does not allow to put
+			// key of size > maxKeySizeInClusters.
+			return nil
+		}
+		var cluster string
+		cluster, rest, _, state = uniseg.FirstGraphemeClusterInString(rest, state)
+		child := current_node.children[cluster]
+		if child == nil {
+			return nil
+		}
+		current_node = child
+	}
+	return current_node
+}
+
+// Removes the key from the PrefixStore and returns if the key was present.
+// Nodes that are left with no key under them are pruned.
+func (t *PrefixStoreGraphemeTrie) Delete(key string) bool {
+	if len(key) == 0 {
+		return false
+	}
+	deleted, _ := t.delete(key, -1)
+	return deleted
+}
+
+// Recursively removes the key under t and returns if the key was present and
+// if t is left with no key under it, so that the parent can prune it.
+func (t *PrefixStoreGraphemeTrie) delete(key string, state int) (bool, bool) {
+	if len(key) == 0 {
+		if !t.isLast {
+			return false, false
+		}
+		t.isLast = false
+		t.count--
+		return true, len(t.children) == 0
+	}
+
+	cluster, rest, _, state := uniseg.FirstGraphemeClusterInString(key, state)
+	child := t.children[cluster]
+	if child == nil {
+		return false, false
+	}
+	deleted, prune := child.delete(rest, state)
+	if deleted {
+		t.count--
+	}
+	if prune {
+		delete(t.children, cluster)
+	}
+	return deleted, prune && !t.isLast && len(t.children) == 0
+}
+
+// Returns the number of keys present in the PrefixStore.
+func (t *PrefixStoreGraphemeTrie) Count() int {
+	return t.count
+}
+
+// Does the prefix search on the PrefixStore and returns all the keys having
+// the prefix as their leading grapheme clusters, in ascending order. A prefix
+// ending in the middle of a grapheme cluster of a key does not match it.
+func (t *PrefixStoreGraphemeTrie) PrefixSearch(prefix string) []string {
+	subTrie := t.get(prefix)
+	if subTrie == nil {
+		return nil
+	}
+
+	var entries []string
+	buffer := make([]byte, 0, len(prefix))
+	buffer = append(buffer, prefix...)
+	subTrie.walk(buffer, func(key string) {
+		entries = append(entries, key)
+	})
+	return entries
+}
+
+// Does a Depth First Search traversal visiting children in ascending order,
+// and calls fn with every key under t appended to buffer.
+func (t *PrefixStoreGraphemeTrie) walk(buffer []byte, fn func(key string)) {
+	if t.isLast {
+		fn(string(buffer))
+	}
+
+	clusters := make([]string, 0, len(t.children))
+	for cluster := range t.children {
+		clusters = append(clusters, cluster)
+	}
+	sort.Strings(clusters)
+
+	for _, cluster := range clusters {
+		t.children[cluster].walk(append(buffer, cluster...), fn)
+	}
+}
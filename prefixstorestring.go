@@ -0,0 +1,204 @@
+package tripod
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// Represents the PrefixStore which uses an in-memory trie data-structure to
+// store keys and efficiently return them when searched by prefix.
+// PrefixStoreString is optimized for type string: keys are traversed by
+// their UTF-8 bytes, so no conversion is needed on the way in, and each
+// result costs a single allocation on the way out.
+type PrefixStoreString struct {
+	isLast            bool
+	children          map[int]*PrefixStoreString
+	maxKeySizeInRunes int
+
+	// Number of keys under the node, itself included, maintained by every
+	// method adding or removing keys.
+	count int
+}
+
+// Creates and returns reference to a new instance of PrefixStoreString.
+// maxKeySizeInRunes is the maximum number of runes in the key that should be
+// allowed to be added to the PrefixStore. When tried to put key of length more
+// than maxKeySizeInRunes, the method will return the error.
+func CreatePrefixStoreString(maxKeySizeInRunes int) *PrefixStoreString {
+	return &PrefixStoreString{
+		children:          make(map[int]*PrefixStoreString),
+		maxKeySizeInRunes: maxKeySizeInRunes,
+	}
+}
+
+// Adds the string key to the PrefixStore and returns if key was succesfully
+// added and any error encountered.
+// A non nil error is returned if key is not valid UTF-8 or if it has more
+// than maxKeySizeInRunes runes.
+func (t *PrefixStoreString) Put(key string) (bool, error) {
+	if !utf8.ValidString(key) {
+		return false, fmt.Errorf("key should be valid UTF-8")
+	}
+	if size := utf8.RuneCountInString(key); size > t.maxKeySizeInRunes {
+		return false, fmt.Errorf("max size of key should be %d (%d > %d)",
+			t.maxKeySizeInRunes, size, t.maxKeySizeInRunes)
+	}
+
+	current_node := t
+	for i := 0; i < len(key); i++ {
+		child := current_node.children[int(key[i])]
+		if child == nil {
+			child = CreatePrefixStoreString(t.maxKeySizeInRunes)
+			current_node.children[int(key[i])] = child
+		}
+		current_node = child
+	}
+
+	// If key is empty then current_node == t, and since nothing was added
+	// hence returning false.
+	if current_node == t {
+		return false, nil
+	}
+
+	if current_node.isLast {
+		return false, nil
+	}
+	current_node.isLast = true
+
+	// The key is new, hence every node on its path has one more key under
+	// it.
+	current_node = t
+	current_node.count++
+	for i := 0; i < len(key); i++ {
+		current_node = current_node.children[int(key[i])]
+		current_node.count++
+	}
+	return true, nil
+}
+
+// Checks and returns if given key is present in the PrefixStore
+func (t *PrefixStoreString) Exists(key string) bool {
+	current_node := t.get(key)
+	return current_node != nil && current_node != t && current_node.isLast
+}
+
+// For a given instance of PrefixStore t, this method returns a reference to
+// subPrefixStore that ends at the key.
+func (t *PrefixStoreString) get(key string) *PrefixStoreString {
+	// Since a key has at most 4 bytes per rune, longer keys cannot be present
+	// and the runes need not be counted.
+	if len(key) > utf8.UTFMax*t.maxKeySizeInRunes {
+		return nil
+	}
+
+	current_node := t
+	for i := 0; i < len(key); i++ {
+		child := current_node.children[int(key[i])]
+		if child == nil {
+			return nil
+		}
+		current_node = child
+	}
+	return current_node
+}
+
+// Removes the key from the PrefixStore and returns if the key was present.
+// Nodes that are left with no key under them are pruned.
+func (t *PrefixStoreString) Delete(key string) bool {
+	if len(key) == 0 || len(key) > utf8.UTFMax*t.maxKeySizeInRunes {
+		return false
+	}
+	deleted, _ := t.delete(key)
+	return deleted
+}
+
+// Recursively removes the key under t and returns if the key was present and
+// if t is left with no key under it, so that the parent can prune it.
+func (t *PrefixStoreString) delete(key string) (bool, bool) {
+	if len(key) == 0 {
+		if !t.isLast {
+			return false, false
+		}
+		t.isLast = false
+		t.count--
+		return true, len(t.children) == 0
+	}
+
+	child := t.children[int(key[0])]
+	if child == nil {
+		return false, false
+	}
+	deleted, prune := child.delete(key[1:])
+	if deleted {
+		t.count--
+	}
+	if prune {
+		delete(t.children, int(key[0]))
+	}
+	return deleted, prune && !t.isLast && len(t.children) == 0
+}
+
+// Returns the number of keys present in the PrefixStore.
+func (t *PrefixStoreString) Count() int {
+	return t.count
+}
+
+// Does the prefix search on the PrefixStore and returns all the keys having
+// the prefix, in ascending order.
+// Since valid UTF-8 is self-synchronizing, a key has the runes of prefix as
+// its first runes if and only if it has the bytes of prefix as its first
+// bytes; hence a prefix ending with an incomplete rune matches no key.
+func (t *PrefixStoreString) PrefixSearch(prefix string) []string {
+	var entries []string
+	t.PrefixSearchFunc(prefix, func(key string) bool {
+		entries = append(entries, key)
+		return true
+	})
+	return entries
+}
+
+// Calls fn with every key having the prefix, in ascending order, until fn
+// returns false. Unlike PrefixSearch, the keys are not collected, hence
+// callers can stop early or stream the keys elsewhere.
+func (t *PrefixStoreString) PrefixSearchFunc(prefix string, fn func(key string) bool) {
+	if !utf8.ValidString(prefix) {
+		return
+	}
+	subTrie := t.get(prefix)
+	if subTrie == nil {
+		return
+	}
+
+	buffer := make([]byte, 0, utf8.UTFMax*t.maxKeySizeInRunes)
+	buffer = append(buffer, prefix...)
+	subTrie.walk(buffer, fn)
+}
+
+// Does a Depth First Search traversal visiting children in ascending order,
+// and calls fn with every key under t appended to buffer. Returns false if
+// fn asked to stop.
+func (t *PrefixStoreString) walk(buffer []byte, fn func(key string) bool) bool {
+	if t.isLast && !fn(string(buffer)) {
+		return false
+	}
+	var scratch [256]byte
+	for _, ch := range t.sortedChildKeys(scratch[:0]) {
+		if !t.children[int(ch)].walk(append(buffer, ch), fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// Appends the keys of the children of t to keys in ascending order. Being
+// at most 256, they are insertion sorted in place, so that a caller passing
+// an array on its stack does not allocate.
+func (t *PrefixStoreString) sortedChildKeys(keys []byte) []byte {
+	for ch := range t.children {
+		keys = append(keys, byte(ch))
+		for i := len(keys) - 1; i > 0 && keys[i-1] > keys[i]; i-- {
+			keys[i-1], keys[i] = keys[i], keys[i-1]
+		}
+	}
+	return keys
+}
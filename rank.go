@@ -0,0 +1,113 @@
+package tripod
+
+// Returns the number of keys of the PrefixStore that sort before key, which
+// need not be present. Thanks to the count of keys kept by every node, it
+// takes time proportional to the length of key rather than to the number of
+// keys.
+func (t *PrefixStoreByteTrie) Rank(key []byte) int {
+	rank := 0
+	current_node := t
+	for _, b := range key {
+		// The path to current_node is a proper prefix of key, hence it
+		// sorts before, as do the keys under the children before b.
+		if current_node.isLast {
+			rank++
+		}
+		for ch, child := range current_node.children {
+			if ch < int(b) {
+				rank += child.count
+			}
+		}
+
+		current_node = current_node.children[int(b)]
+		if current_node == nil {
+			break
+		}
+	}
+	return rank
+}
+
+// Returns the key of the PrefixStore at index i in ascending order, that is
+// the key of rank i, and if there is one. It takes time proportional to the
+// length of the key rather than to the number of keys.
+func (t *PrefixStoreByteTrie) Select(i int) ([]byte, bool) {
+	if i < 0 || i >= t.count {
+		return nil, false
+	}
+
+	var key []byte
+	current_node := t
+	for {
+		if current_node.isLast {
+			if i == 0 {
+				return key, true
+			}
+			i--
+		}
+		for _, ch := range current_node.sortedChildKeys() {
+			child := current_node.children[ch]
+			if i < child.count {
+				key = append(key, byte(ch))
+				current_node = child
+				break
+			}
+			i -= child.count
+		}
+	}
+}
+
+// Returns the number of keys of the PrefixStore that sort before key, which
+// need not be present. Thanks to the count of keys kept by every node, it
+// takes time proportional to the length of key rather than to the number of
+// keys.
+func (t *PrefixStoreRuneTrie) Rank(key []rune) int {
+	rank := 0
+	current_node := t
+	for _, r := range key {
+		// The path to current_node is a proper prefix of key, hence it
+		// sorts before, as do the keys under the children before r.
+		if current_node.isLast {
+			rank++
+		}
+		for ch, child := range current_node.children {
+			if ch < r {
+				rank += child.count
+			}
+		}
+
+		current_node = current_node.children[r]
+		if current_node == nil {
+			break
+		}
+	}
+	return rank
+}
+
+// Returns the key of the PrefixStore at index i in ascending order, that is
+// the key of rank i, and if there is one. It takes time proportional to the
+// length of the key rather than to the number of keys.
+func (t *PrefixStoreRuneTrie) Select(i int) ([]rune, bool) {
+	if i < 0 || i >= t.count {
+		return nil, false
+	}
+
+	var key []rune
+	current_node := t
+	for {
+		if current_node.isLast {
+			if i == 0 {
+				return key, true
+			}
+			i--
+		}
+		for _, ch := range current_node.sortedChildKeys() {
+			child := current_node.children[ch]
+			if i < child.count {
+				key = append(key, ch)
+				current_node = child
+				break
+			}
+			i -= child.count
+		}
+	}
+}
@@ -0,0 +1,310 @@
+package remote
+
+import (
+	"container/list"
+	"context"
+	"github.com/arpitbbhayani/tripod"
+	"github.com/arpitbbhayani/tripod/remote/tripodpb"
+	"google.golang.org/grpc"
+	"io"
+	"sync"
+	"time"
+)
+
+// Accesses the stores served by a Server.
+type Client struct {
+	client  tripodpb.PrefixStoreServiceClient
+	timeout time.Duration
+}
+
+// Creates and returns reference to a new Client over conn. Every call made
+// through the stores of the client is given timeout to complete; 0 means no
+// timeout.
+func CreateClient(conn grpc.ClientConnInterface, timeout time.Duration) *Client {
+	return &Client{
+		client:  tripodpb.NewPrefixStoreServiceClient(conn),
+		timeout: timeout,
+	}
+}
+
+// Returns the context for a single call.
+func (c *Client) context() (context.Context, context.CancelFunc) {
+	if c.timeout == 0 {
+		return context.WithCancel(context.Background())
+	}
+	return context.WithTimeout(context.Background(), c.timeout)
+}
+
+// Returns the remote store served under name as a BytePrefixStore. Every
+// call site should get its own store, as the error of a failed call is
+// retained by the store until checked with Err.
+func (c *Client) ByteStore(name string) *ByteStore {
+	return &ByteStore{remoteStore{client: c, name: name}}
+}
+
+// Returns the remote store served under name as a RunePrefixStore. Every
+// call site should get its own store, as the error of a failed call is
+// retained by the store until checked with Err.
+func (c *Client) RuneStore(name string) *RuneStore {
+	return &RuneStore{remoteStore{client: c, name: name}}
+}
+
+// Calls common to ByteStore and RuneStore, on keys encoded as []byte.
+//
+// Every call has a variant taking a context and returning its own error,
+// which should be preferred, especially when the store is shared by
+// goroutines. Exists, PrefixSearch, Delete and Count have no error in their
+// signature, as in the local stores: when such a call fails, it returns the
+// zero value, which cannot be told apart from an absent key or an empty
+// store, and the error is retained. Callers must check Err after them.
+type remoteStore struct {
+	client *Client
+	name   string
+
+	mu  sync.Mutex
+	err error
+}
+
+// Returns the error of the last failed call without an error in its
+// signature, if any, and clears it.
+func (s *remoteStore) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	err := s.err
+	s.err = nil
+	return err
+}
+
+// Retains err, if not nil, for Err.
+func (s *remoteStore) retain(err error) {
+	if err == nil {
+		return
+	}
+	s.mu.Lock()
+	s.err = err
+	s.mu.Unlock()
+}
+
+func (s *remoteStore) put(ctx context.Context, key []byte) (bool, error) {
+	resp, err := s.client.client.Put(ctx, &tripodpb.PutRequest{Store: s.name, Key: key})
+	if err != nil {
+		return false, err
+	}
+	return resp.GetAdded(), nil
+}
+
+func (s *remoteStore) exists(ctx context.Context, key []byte) (bool, error) {
+	resp, err := s.client.client.Exists(ctx, &tripodpb.ExistsRequest{Store: s.name, Key: key})
+	if err != nil {
+		return false, err
+	}
+	return resp.GetExists(), nil
+}
+
+// Calls fn with the keys streamed back for the prefix, in ascending order and
+// at most limit of them unless limit is 0.
+func (s *remoteStore) prefixSearch(ctx context.Context, prefix []byte, limit int, fn func(key []byte)) error {
+	stream, err := s.client.client.PrefixSearch(ctx, &tripodpb.PrefixSearchRequest{
+		Store:  s.name,
+		Prefix: prefix,
+		Limit:  uint32(limit),
+	})
+	if err != nil {
+		return err
+	}
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		for _, key := range resp.GetKeys() {
+			fn(key)
+		}
+	}
+}
+
+func (s *remoteStore) delete(ctx context.Context, key []byte) (bool, error) {
+	resp, err := s.client.client.Delete(ctx, &tripodpb.DeleteRequest{Store: s.name, Key: key})
+	if err != nil {
+		return false, err
+	}
+	return resp.GetDeleted(), nil
+}
+
+// Returns the number of keys in the remote store and any error encountered.
+func (s *remoteStore) CountContext(ctx context.Context) (int, error) {
+	resp, err := s.client.client.Count(ctx, &tripodpb.CountRequest{Store: s.name})
+	if err != nil {
+		return 0, err
+	}
+	return int(resp.GetCount()), nil
+}
+
+// Returns the number of keys in the remote store, or 0 if the call fails, in
+// which case the error is retained for Err.
+func (s *remoteStore) Count() int {
+	ctx, cancel := s.client.context()
+	defer cancel()
+	count, err := s.CountContext(ctx)
+	s.retain(err)
+	return count
+}
+
+// A remote store accessed as a BytePrefixStore.
+type ByteStore struct {
+	remoteStore
+}
+
+var _ tripod.BytePrefixStore = (*ByteStore)(nil)
+
+// Adds the key to the remote store and returns if key was succesfully added
+// and any error encountered.
+func (s *ByteStore) PutContext(ctx context.Context, key []byte) (bool, error) {
+	return s.put(ctx, key)
+}
+
+// Adds the key to the remote store and returns if key was succesfully added
+// and any error encountered.
+func (s *ByteStore) Put(key []byte) (bool, error) {
+	ctx, cancel := s.client.context()
+	defer cancel()
+	return s.put(ctx, key)
+}
+
+// Checks and returns if given key is present in the remote store, and any
+// error encountered.
+func (s *ByteStore) ExistsContext(ctx context.Context, key []byte) (bool, error) {
+	return s.exists(ctx, key)
+}
+
+// Checks and returns if given key is present in the remote store. It returns
+// false if the call fails, in which case the error is retained for Err.
+func (s *ByteStore) Exists(key []byte) bool {
+	ctx, cancel := s.client.context()
+	defer cancel()
+	exists, err := s.exists(ctx, key)
+	s.retain(err)
+	return exists
+}
+
+// Does the prefix search on the remote store and returns a reference to
+// list (*list.List) containing the first limit entries for the given prefix
+// in ascending order, all of them if limit is 0, and any error encountered.
+// Each element of the list is []byte.
+func (s *ByteStore) PrefixSearchContext(ctx context.Context, prefix []byte, limit int) (*list.List, error) {
+	entries := list.New()
+	err := s.prefixSearch(ctx, prefix, limit, func(key []byte) {
+		entries.PushBack(key)
+	})
+	return entries, err
+}
+
+// Does the prefix search on the remote store and returns a reference to
+// list (*list.List) containings all entries for the given prefix. Each
+// element of the list is []byte. If the call fails, the entries received so
+// far are returned and the error is retained for Err.
+func (s *ByteStore) PrefixSearch(prefix []byte) *list.List {
+	ctx, cancel := s.client.context()
+	defer cancel()
+	entries, err := s.PrefixSearchContext(ctx, prefix, 0)
+	s.retain(err)
+	return entries
+}
+
+// Removes the key from the remote store and returns if the key was present,
+// and any error encountered.
+func (s *ByteStore) DeleteContext(ctx context.Context, key []byte) (bool, error) {
+	return s.delete(ctx, key)
+}
+
+// Removes the key from the remote store and returns if the key was present.
+// It returns false if the call fails, in which case the error is retained
+// for Err.
+func (s *ByteStore) Delete(key []byte) bool {
+	ctx, cancel := s.client.context()
+	defer cancel()
+	deleted, err := s.delete(ctx, key)
+	s.retain(err)
+	return deleted
+}
+
+// A remote store accessed as a RunePrefixStore.
+type RuneStore struct {
+	remoteStore
+}
+
+var _ tripod.RunePrefixStore = (*RuneStore)(nil)
+
+// Adds the key to the remote store and returns if key was succesfully added
+// and any error encountered.
+func (s *RuneStore) PutContext(ctx context.Context, key []rune) (bool, error) {
+	return s.put(ctx, []byte(string(key)))
+}
+
+// Adds the key to the remote store and returns if key was succesfully added
+// and any error encountered.
+func (s *RuneStore) Put(key []rune) (bool, error) {
+	ctx, cancel := s.client.context()
+	defer cancel()
+	return s.put(ctx, []byte(string(key)))
+}
+
+// Checks and returns if given key is present in the remote store, and any
+// error encountered.
+func (s *RuneStore) ExistsContext(ctx context.Context, key []rune) (bool, error) {
+	return s.exists(ctx, []byte(string(key)))
+}
+
+// Checks and returns if given key is present in the remote store. It returns
+// false if the call fails, in which case the error is retained for Err.
+func (s *RuneStore) Exists(key []rune) bool {
+	ctx, cancel := s.client.context()
+	defer cancel()
+	exists, err := s.exists(ctx, []byte(string(key)))
+	s.retain(err)
+	return exists
+}
+
+// Does the prefix search on the remote store and returns a reference to
+// list (*list.List) containing the first limit entries for the given prefix
+// in ascending order, all of them if limit is 0, and any error encountered.
+// Each element of the list is []rune.
+func (s *RuneStore) PrefixSearchContext(ctx context.Context, prefix []rune, limit int) (*list.List, error) {
+	entries := list.New()
+	err := s.prefixSearch(ctx, []byte(string(prefix)), limit, func(key []byte) {
+		entries.PushBack([]rune(string(key)))
+	})
+	return entries, err
+}
+
+// Does the prefix search on the remote store and returns a reference to
+// list (*list.List) containings all entries for the given prefix. Each
+// element of the list is []rune. If the call fails, the entries received so
+// far are returned and the error is retained for Err.
+func (s *RuneStore) PrefixSearch(prefix []rune) *list.List {
+	ctx, cancel := s.client.context()
+	defer cancel()
+	entries, err := s.PrefixSearchContext(ctx, prefix, 0)
+	s.retain(err)
+	return entries
+}
+
+// Removes the key from the remote store and returns if the key was present,
+// and any error encountered.
+func (s *RuneStore) DeleteContext(ctx context.Context, key []rune) (bool, error) {
+	return s.delete(ctx, []byte(string(key)))
+}
+
+// Removes the key from the remote store and returns if the key was present.
+// It returns false if the call fails, in which case the error is retained
+// for Err.
+func (s *RuneStore) Delete(key []rune) bool {
+	ctx, cancel := s.client.context()
+	defer cancel()
+	deleted, err := s.delete(ctx, []byte(string(key)))
+	s.retain(err)
+	return deleted
+}
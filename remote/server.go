@@ -0,0 +1,259 @@
+// Package remote serves PrefixStores over gRPC and provides clients that
+// implement the same interfaces as the local stores, hence code can switch
+// between local and remote stores without changes.
+package remote
+
+import (
+	"bytes"
+	"context"
+	"github.com/arpitbbhayani/tripod"
+	"github.com/arpitbbhayani/tripod/remote/tripodpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"sort"
+	"sync"
+)
+
+// Number of keys sent per message while streaming PrefixSearch results.
+const prefixSearchBatchSize = 256
+
+// A named store of either kind, guarded for concurrent requests.
+type namedStore struct {
+	mu    sync.RWMutex
+	bytes tripod.BytePrefixStore
+	runes tripod.RunePrefixStore
+}
+
+// Serves named PrefixStores over gRPC. Register it on a grpc.Server with
+// tripodpb.RegisterPrefixStoreServiceServer.
+type Server struct {
+	tripodpb.UnimplementedPrefixStoreServiceServer
+
+	mu     sync.RWMutex
+	stores map[string]*namedStore
+}
+
+// Creates and returns reference to a new Server with no stores.
+func CreateServer() *Server {
+	return &Server{stores: make(map[string]*namedStore)}
+}
+
+// Serves the store under name, replacing any store served under it.
+// The Server serializes access to the store, hence the store must not be
+// modified elsewhere while it is served.
+func (s *Server) AddByteStore(name string, store tripod.BytePrefixStore) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stores[name] = &namedStore{bytes: store}
+}
+
+// Serves the store under name, replacing any store served under it. Keys
+// are exchanged encoded in UTF-8.
+// The Server serializes access to the store, hence the store must not be
+// modified elsewhere while it is served.
+func (s *Server) AddRuneStore(name string, store tripod.RunePrefixStore) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stores[name] = &namedStore{runes: store}
+}
+
+// Returns the store with given name or a NotFound error.
+func (s *Server) lookup(name string) (*namedStore, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	store, ok := s.stores[name]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown store %q", name)
+	}
+	return store, nil
+}
+
+func (s *Server) Put(ctx context.Context, req *tripodpb.PutRequest) (*tripodpb.PutResponse, error) {
+	store, err := s.lookup(req.GetStore())
+	if err != nil {
+		return nil, err
+	}
+
+	store.mu.Lock()
+	var added bool
+	if store.bytes != nil {
+		added, err = store.bytes.Put(req.GetKey())
+	} else {
+		added, err = store.runes.Put([]rune(string(req.GetKey())))
+	}
+	store.mu.Unlock()
+
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return &tripodpb.PutResponse{Added: added}, nil
+}
+
+func (s *Server) Exists(ctx context.Context, req *tripodpb.ExistsRequest) (*tripodpb.ExistsResponse, error) {
+	store, err := s.lookup(req.GetStore())
+	if err != nil {
+		return nil, err
+	}
+
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+	if store.bytes != nil {
+		return &tripodpb.ExistsResponse{Exists: store.bytes.Exists(req.GetKey())}, nil
+	}
+	return &tripodpb.ExistsResponse{Exists: store.runes.Exists([]rune(string(req.GetKey())))}, nil
+}
+
+func (s *Server) PrefixSearch(req *tripodpb.PrefixSearchRequest, stream tripodpb.PrefixStoreService_PrefixSearchServer) error {
+	store, err := s.lookup(req.GetStore())
+	if err != nil {
+		return err
+	}
+
+	prefix := req.GetPrefix()
+	if store.runes != nil {
+		// Rune stores see the prefix decoded from UTF-8.
+		prefix = []byte(string([]rune(string(prefix))))
+	}
+	limit := int(req.GetLimit())
+
+	// Stores walking their keys in order are walked in batches, the lock
+	// being released while a batch is sent, so that a slow client does not
+	// block writers. Every batch resumes after the last key sent.
+	var after []byte
+	for sent := 0; limit == 0 || sent < limit; {
+		n := limit - sent
+		if limit == 0 {
+			n = -1
+		}
+		if store.ordered() && (n < 0 || n > prefixSearchBatchSize) {
+			n = prefixSearchBatchSize
+		}
+		keys, more := store.keysAfter(prefix, after, n)
+		if len(keys) > 0 {
+			after = keys[len(keys)-1]
+			sent += len(keys)
+		}
+		for len(keys) > 0 {
+			batch := keys[:min(len(keys), prefixSearchBatchSize)]
+			if err := stream.Send(&tripodpb.PrefixSearchResponse{Keys: batch}); err != nil {
+				return err
+			}
+			keys = keys[len(batch):]
+		}
+		if !more {
+			break
+		}
+	}
+	return nil
+}
+
+func (s *Server) Delete(ctx context.Context, req *tripodpb.DeleteRequest) (*tripodpb.DeleteResponse, error) {
+	store, err := s.lookup(req.GetStore())
+	if err != nil {
+		return nil, err
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if store.bytes != nil {
+		return &tripodpb.DeleteResponse{Deleted: store.bytes.Delete(req.GetKey())}, nil
+	}
+	return &tripodpb.DeleteResponse{Deleted: store.runes.Delete([]rune(string(req.GetKey())))}, nil
+}
+
+func (s *Server) Count(ctx context.Context, req *tripodpb.CountRequest) (*tripodpb.CountResponse, error) {
+	store, err := s.lookup(req.GetStore())
+	if err != nil {
+		return nil, err
+	}
+
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+	if store.bytes != nil {
+		return &tripodpb.CountResponse{Count: uint64(store.bytes.Count())}, nil
+	}
+	return &tripodpb.CountResponse{Count: uint64(store.runes.Count())}, nil
+}
+
+// A PrefixStore walking its keys in ascending order, like
+// PrefixStoreByteTrie and PrefixStoreRuneTrie.
+type byteRanger interface {
+	Range(from, to []byte, fn func(key []byte) bool)
+}
+
+type runeRanger interface {
+	Range(from, to []rune, fn func(key []rune) bool)
+}
+
+// Checks and returns if the store walks its keys in order.
+func (store *namedStore) ordered() bool {
+	_, bytesOrdered := store.bytes.(byteRanger)
+	_, runesOrdered := store.runes.(runeRanger)
+	return bytesOrdered || runesOrdered
+}
+
+// Returns, in ascending order, the first n keys of the store, or all of them
+// if n is negative, having prefix and greater than after, if not nil, and if
+// there are more of them.
+// Stores that do not walk their keys in order are searched and sorted as a
+// whole on every call.
+func (store *namedStore) keysAfter(prefix, after []byte, n int) ([][]byte, bool) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	from := prefix
+	if after != nil {
+		from = after
+	}
+	var keys [][]byte
+	more := false
+	collect := func(key []byte) bool {
+		if !bytes.HasPrefix(key, prefix) {
+			return false
+		}
+		if after != nil && bytes.Compare(key, after) <= 0 {
+			return true
+		}
+		if len(keys) == n {
+			more = true
+			return false
+		}
+		keys = append(keys, key)
+		return true
+	}
+
+	if r, ok := store.bytes.(byteRanger); ok {
+		r.Range(from, nil, func(key []byte) bool {
+			return collect(bytes.Clone(key))
+		})
+		return keys, more
+	}
+	if r, ok := store.runes.(runeRanger); ok {
+		r.Range([]rune(string(from)), nil, func(key []rune) bool {
+			return collect([]byte(string(key)))
+		})
+		return keys, more
+	}
+
+	var all [][]byte
+	if store.bytes != nil {
+		results := store.bytes.PrefixSearch(prefix)
+		for e := results.Front(); e != nil; e = e.Next() {
+			all = append(all, e.Value.([]byte))
+		}
+	} else {
+		results := store.runes.PrefixSearch([]rune(string(prefix)))
+		for e := results.Front(); e != nil; e = e.Next() {
+			all = append(all, []byte(string(e.Value.([]rune))))
+		}
+	}
+	sort.Slice(all, func(i, j int) bool {
+		return bytes.Compare(all[i], all[j]) < 0
+	})
+	for _, key := range all {
+		if !collect(key) {
+			break
+		}
+	}
+	return keys, more
+}
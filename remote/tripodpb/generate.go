@@ -0,0 +1,5 @@
+// Package tripodpb holds the protobuf messages and the gRPC service of the
+// remote prefix stores, generated from tripod.proto.
+package tripodpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative tripod.proto
@@ -0,0 +1,297 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: tripod.proto
+
+// Remote access to named prefix stores.
+
+package tripodpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	PrefixStoreService_Put_FullMethodName          = "/tripod.v1.PrefixStoreService/Put"
+	PrefixStoreService_Exists_FullMethodName       = "/tripod.v1.PrefixStoreService/Exists"
+	PrefixStoreService_PrefixSearch_FullMethodName = "/tripod.v1.PrefixStoreService/PrefixSearch"
+	PrefixStoreService_Delete_FullMethodName       = "/tripod.v1.PrefixStoreService/Delete"
+	PrefixStoreService_Count_FullMethodName        = "/tripod.v1.PrefixStoreService/Count"
+)
+
+// PrefixStoreServiceClient is the client API for PrefixStoreService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Serves named prefix stores. Keys are bytes; stores holding runes take and
+// return keys encoded in UTF-8.
+type PrefixStoreServiceClient interface {
+	// Adds the key to the store.
+	Put(ctx context.Context, in *PutRequest, opts ...grpc.CallOption) (*PutResponse, error)
+	// Checks if the key is present in the store.
+	Exists(ctx context.Context, in *ExistsRequest, opts ...grpc.CallOption) (*ExistsResponse, error)
+	// Streams the keys of the store having the prefix in ascending order, in
+	// batches.
+	PrefixSearch(ctx context.Context, in *PrefixSearchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PrefixSearchResponse], error)
+	// Removes the key from the store.
+	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+	// Returns the number of keys in the store.
+	Count(ctx context.Context, in *CountRequest, opts ...grpc.CallOption) (*CountResponse, error)
+}
+
+type prefixStoreServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPrefixStoreServiceClient(cc grpc.ClientConnInterface) PrefixStoreServiceClient {
+	return &prefixStoreServiceClient{cc}
+}
+
+func (c *prefixStoreServiceClient) Put(ctx context.Context, in *PutRequest, opts ...grpc.CallOption) (*PutResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PutResponse)
+	err := c.cc.Invoke(ctx, PrefixStoreService_Put_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *prefixStoreServiceClient) Exists(ctx context.Context, in *ExistsRequest, opts ...grpc.CallOption) (*ExistsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExistsResponse)
+	err := c.cc.Invoke(ctx, PrefixStoreService_Exists_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *prefixStoreServiceClient) PrefixSearch(ctx context.Context, in *PrefixSearchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PrefixSearchResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PrefixStoreService_ServiceDesc.Streams[0], PrefixStoreService_PrefixSearch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[PrefixSearchRequest, PrefixSearchResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PrefixStoreService_PrefixSearchClient = grpc.ServerStreamingClient[PrefixSearchResponse]
+
+func (c *prefixStoreServiceClient) Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, PrefixStoreService_Delete_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *prefixStoreServiceClient) Count(ctx context.Context, in *CountRequest, opts ...grpc.CallOption) (*CountResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CountResponse)
+	err := c.cc.Invoke(ctx, PrefixStoreService_Count_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PrefixStoreServiceServer is the server API for PrefixStoreService service.
+// All implementations must embed UnimplementedPrefixStoreServiceServer
+// for forward compatibility.
+//
+// Serves named prefix stores. Keys are bytes; stores holding runes take and
+// return keys encoded in UTF-8.
+type PrefixStoreServiceServer interface {
+	// Adds the key to the store.
+	Put(context.Context, *PutRequest) (*PutResponse, error)
+	// Checks if the key is present in the store.
+	Exists(context.Context, *ExistsRequest) (*ExistsResponse, error)
+	// Streams the keys of the store having the prefix in ascending order, in
+	// batches.
+	PrefixSearch(*PrefixSearchRequest, grpc.ServerStreamingServer[PrefixSearchResponse]) error
+	// Removes the key from the store.
+	Delete(context.Context, *DeleteRequest) (*DeleteResponse, error)
+	// Returns the number of keys in the store.
+	Count(context.Context, *CountRequest) (*CountResponse, error)
+	mustEmbedUnimplementedPrefixStoreServiceServer()
+}
+
+// UnimplementedPrefixStoreServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedPrefixStoreServiceServer struct{}
+
+func (UnimplementedPrefixStoreServiceServer) Put(context.Context, *PutRequest) (*PutResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Put not implemented")
+}
+func (UnimplementedPrefixStoreServiceServer) Exists(context.Context, *ExistsRequest) (*ExistsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Exists not implemented")
+}
+func (UnimplementedPrefixStoreServiceServer) PrefixSearch(*PrefixSearchRequest, grpc.ServerStreamingServer[PrefixSearchResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method PrefixSearch not implemented")
+}
+func (UnimplementedPrefixStoreServiceServer) Delete(context.Context, *DeleteRequest) (*DeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
+}
+func (UnimplementedPrefixStoreServiceServer) Count(context.Context, *CountRequest) (*CountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Count not implemented")
+}
+func (UnimplementedPrefixStoreServiceServer) mustEmbedUnimplementedPrefixStoreServiceServer() {}
+func (UnimplementedPrefixStoreServiceServer) testEmbeddedByValue()                            {}
+
+// UnsafePrefixStoreServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PrefixStoreServiceServer will
+// result in compilation errors.
+type UnsafePrefixStoreServiceServer interface {
+	mustEmbedUnimplementedPrefixStoreServiceServer()
+}
+
+func RegisterPrefixStoreServiceServer(s grpc.ServiceRegistrar, srv PrefixStoreServiceServer) {
+	// If the following call pancis, it indicates UnimplementedPrefixStoreServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&PrefixStoreService_ServiceDesc, srv)
+}
+
+func _PrefixStoreService_Put_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PrefixStoreServiceServer).Put(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PrefixStoreService_Put_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PrefixStoreServiceServer).Put(ctx, req.(*PutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PrefixStoreService_Exists_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExistsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PrefixStoreServiceServer).Exists(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PrefixStoreService_Exists_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PrefixStoreServiceServer).Exists(ctx, req.(*ExistsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PrefixStoreService_PrefixSearch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(PrefixSearchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PrefixStoreServiceServer).PrefixSearch(m, &grpc.GenericServerStream[PrefixSearchRequest, PrefixSearchResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PrefixStoreService_PrefixSearchServer = grpc.ServerStreamingServer[PrefixSearchResponse]
+
+func _PrefixStoreService_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PrefixStoreServiceServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PrefixStoreService_Delete_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PrefixStoreServiceServer).Delete(ctx, req.(*DeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PrefixStoreService_Count_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PrefixStoreServiceServer).Count(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PrefixStoreService_Count_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PrefixStoreServiceServer).Count(ctx, req.(*CountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// PrefixStoreService_ServiceDesc is the grpc.ServiceDesc for PrefixStoreService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PrefixStoreService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "tripod.v1.PrefixStoreService",
+	HandlerType: (*PrefixStoreServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Put",
+			Handler:    _PrefixStoreService_Put_Handler,
+		},
+		{
+			MethodName: "Exists",
+			Handler:    _PrefixStoreService_Exists_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _PrefixStoreService_Delete_Handler,
+		},
+		{
+			MethodName: "Count",
+			Handler:    _PrefixStoreService_Count_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "PrefixSearch",
+			Handler:       _PrefixStoreService_PrefixSearch_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "tripod.proto",
+}
@@ -0,0 +1,444 @@
+// Package resp serves PrefixStores over the Redis serialization protocol
+// (RESP), hence any Redis client, including redis-cli, can use them through
+// the commands
+//
+//	TPUT store key                  1 if the key was added, else 0
+//	TEXISTS store key               1 if the key is present, else 0
+//	TPREFIX store prefix [LIMIT n]  array of the keys having the prefix
+//	TDEL store key                  1 if the key was deleted, else 0
+//	TCOUNT store                    number of keys in the store
+//
+// along with PING and QUIT.
+package resp
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"github.com/arpitbbhayani/tripod"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Maximum size of a bulk string in a request and maximum number of elements
+// in a request array, so that a client cannot make the server allocate
+// unbounded memory.
+const (
+	maxBulkBytes     = 1 << 20
+	maxArrayElements = 1024
+)
+
+// Number of arguments, including the command itself, taken by each command.
+// A negative arity is the minimum number of arguments.
+var commandArity = map[string]int{
+	"PING":    -1,
+	"QUIT":    1,
+	"TPUT":    3,
+	"TEXISTS": 3,
+	"TPREFIX": -3,
+	"TDEL":    3,
+	"TCOUNT":  2,
+}
+
+// Returned by Serve once the Server is closed.
+var ErrServerClosed = errors.New("resp: server closed")
+
+// A named store of either kind, guarded for concurrent connections.
+type namedStore struct {
+	mu    sync.RWMutex
+	bytes tripod.BytePrefixStore
+	runes tripod.RunePrefixStore
+}
+
+// Serves named PrefixStores over RESP.
+type Server struct {
+	mu       sync.RWMutex
+	stores   map[string]*namedStore
+	listener net.Listener
+	conns    map[net.Conn]struct{}
+	closed   bool
+	wg       sync.WaitGroup
+}
+
+// Creates and returns reference to a new Server with no stores.
+func CreateServer() *Server {
+	return &Server{
+		stores: make(map[string]*namedStore),
+		conns:  make(map[net.Conn]struct{}),
+	}
+}
+
+// Serves the store under name, replacing any store served under it.
+// The Server serializes access to the store, hence the store must not be
+// modified elsewhere while it is served.
+func (s *Server) AddByteStore(name string, store tripod.BytePrefixStore) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stores[name] = &namedStore{bytes: store}
+}
+
+// Serves the store under name, replacing any store served under it. Keys
+// are exchanged encoded in UTF-8.
+// The Server serializes access to the store, hence the store must not be
+// modified elsewhere while it is served.
+func (s *Server) AddRuneStore(name string, store tripod.RunePrefixStore) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stores[name] = &namedStore{runes: store}
+}
+
+// Accepts connections on l and serves each of them in its own goroutine.
+// Serve blocks until the Server is closed, returning ErrServerClosed, or
+// until accepting fails.
+func (s *Server) Serve(l net.Listener) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return ErrServerClosed
+	}
+	s.listener = l
+	s.mu.Unlock()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			s.mu.RLock()
+			closed := s.closed
+			s.mu.RUnlock()
+			if closed {
+				return ErrServerClosed
+			}
+			return err
+		}
+
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			conn.Close()
+			return ErrServerClosed
+		}
+		s.conns[conn] = struct{}{}
+		s.wg.Add(1)
+		s.mu.Unlock()
+
+		go s.serveConn(conn)
+	}
+}
+
+// Stops accepting connections, closes the open ones and waits for their
+// goroutines to return.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	var err error
+	if s.listener != nil {
+		err = s.listener.Close()
+	}
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return err
+}
+
+// Reads commands from conn and writes back their replies until the client
+// quits or the connection fails.
+func (s *Server) serveConn(conn net.Conn) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		conn.Close()
+		s.wg.Done()
+	}()
+
+	r := bufio.NewReader(conn)
+	w := bufio.NewWriter(conn)
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			if err != io.EOF {
+				// Protocol errors leave the stream in an unknown state,
+				// hence the connection is closed after reporting them.
+				writeError(w, err.Error())
+				w.Flush()
+			}
+			return
+		}
+		if len(args) == 0 {
+			continue
+		}
+
+		quit := s.execute(w, args)
+
+		// Pipelined commands are replied to in one write.
+		if r.Buffered() == 0 || quit {
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+		if quit {
+			return
+		}
+	}
+}
+
+// Runs the command and writes its reply. Returns true if the client asked
+// to quit.
+func (s *Server) execute(w *bufio.Writer, args []string) bool {
+	command := strings.ToUpper(args[0])
+	expected, ok := commandArity[command]
+	if !ok {
+		writeError(w, fmt.Sprintf("ERR unknown command '%s'", args[0]))
+		return false
+	}
+	if (expected > 0 && len(args) != expected) || (expected < 0 && len(args) < -expected) {
+		writeError(w, fmt.Sprintf("ERR wrong number of arguments for '%s' command", strings.ToLower(args[0])))
+		return false
+	}
+
+	switch command {
+	case "PING":
+		if len(args) > 1 {
+			writeBulk(w, args[1])
+		} else {
+			w.WriteString("+PONG\r\n")
+		}
+		return false
+	case "QUIT":
+		w.WriteString("+OK\r\n")
+		return true
+	}
+
+	store := s.lookup(args[1])
+	if store == nil {
+		writeError(w, fmt.Sprintf("ERR unknown store '%s'", args[1]))
+		return false
+	}
+
+	switch command {
+	case "TPUT":
+		store.mu.Lock()
+		var added bool
+		var err error
+		if store.bytes != nil {
+			added, err = store.bytes.Put([]byte(args[2]))
+		} else {
+			added, err = store.runes.Put([]rune(args[2]))
+		}
+		store.mu.Unlock()
+		if err != nil {
+			writeError(w, "ERR "+err.Error())
+		} else {
+			writeBool(w, added)
+		}
+	case "TEXISTS":
+		store.mu.RLock()
+		if store.bytes != nil {
+			writeBool(w, store.bytes.Exists([]byte(args[2])))
+		} else {
+			writeBool(w, store.runes.Exists([]rune(args[2])))
+		}
+		store.mu.RUnlock()
+	case "TPREFIX":
+		limit, err := parseLimit(args[3:])
+		if err != nil {
+			writeError(w, err.Error())
+			return false
+		}
+		keys := store.prefixSearch(args[2], limit)
+		fmt.Fprintf(w, "*%d\r\n", len(keys))
+		for _, key := range keys {
+			writeBulk(w, key)
+		}
+	case "TDEL":
+		store.mu.Lock()
+		if store.bytes != nil {
+			writeBool(w, store.bytes.Delete([]byte(args[2])))
+		} else {
+			writeBool(w, store.runes.Delete([]rune(args[2])))
+		}
+		store.mu.Unlock()
+	case "TCOUNT":
+		store.mu.RLock()
+		if store.bytes != nil {
+			fmt.Fprintf(w, ":%d\r\n", store.bytes.Count())
+		} else {
+			fmt.Fprintf(w, ":%d\r\n", store.runes.Count())
+		}
+		store.mu.RUnlock()
+	}
+	return false
+}
+
+// Returns the store with given name, or nil if there is none.
+func (s *Server) lookup(name string) *namedStore {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.stores[name]
+}
+
+// A PrefixStore walking its keys in ascending order, like
+// PrefixStoreByteTrie and PrefixStoreRuneTrie.
+type byteRanger interface {
+	Range(from, to []byte, fn func(key []byte) bool)
+}
+
+type runeRanger interface {
+	Range(from, to []rune, fn func(key []rune) bool)
+}
+
+// Returns the first limit keys having the prefix in ascending order, or all
+// of them if limit is 0. Stores walking their keys in order stop the walk at
+// the limit; the others are searched and sorted as a whole.
+func (store *namedStore) prefixSearch(prefix string, limit int) []string {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	var keys []string
+	collect := func(key string) bool {
+		if !strings.HasPrefix(key, prefix) || (limit > 0 && len(keys) == limit) {
+			return false
+		}
+		keys = append(keys, key)
+		return true
+	}
+
+	if r, ok := store.bytes.(byteRanger); ok {
+		r.Range([]byte(prefix), nil, func(key []byte) bool {
+			return collect(string(key))
+		})
+		return keys
+	}
+	if r, ok := store.runes.(runeRanger); ok {
+		// Rune stores see the prefix decoded from UTF-8.
+		prefix = string([]rune(prefix))
+		r.Range([]rune(prefix), nil, func(key []rune) bool {
+			return collect(string(key))
+		})
+		return keys
+	}
+
+	if store.bytes != nil {
+		results := store.bytes.PrefixSearch([]byte(prefix))
+		keys = make([]string, 0, results.Len())
+		for e := results.Front(); e != nil; e = e.Next() {
+			keys = append(keys, string(e.Value.([]byte)))
+		}
+	} else {
+		results := store.runes.PrefixSearch([]rune(prefix))
+		keys = make([]string, 0, results.Len())
+		for e := results.Front(); e != nil; e = e.Next() {
+			keys = append(keys, string(e.Value.([]rune)))
+		}
+	}
+	sort.Strings(keys)
+	if limit > 0 && len(keys) > limit {
+		keys = keys[:limit]
+	}
+	return keys
+}
+
+// Parses the optional "LIMIT n" trailing TPREFIX. 0 means no limit.
+func parseLimit(args []string) (int, error) {
+	if len(args) == 0 {
+		return 0, nil
+	}
+	if len(args) != 2 || strings.ToUpper(args[0]) != "LIMIT" {
+		return 0, errors.New("ERR syntax error")
+	}
+	limit, err := strconv.Atoi(args[1])
+	if err != nil || limit < 0 {
+		return 0, errors.New("ERR LIMIT should be a non-negative integer")
+	}
+	return limit, nil
+}
+
+// Reads a command, either as a RESP array of bulk strings as sent by the
+// clients, or as an inline command as typed in telnet.
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 || line[0] != '*' {
+		return strings.Fields(line), nil
+	}
+
+	count, err := strconv.Atoi(line[1:])
+	if err != nil || count > maxArrayElements {
+		return nil, errors.New("ERR Protocol error: invalid multibulk length")
+	}
+	if count <= 0 {
+		// Like Redis, empty and null arrays, and arrays of negative length,
+		// are skipped.
+		return nil, nil
+	}
+	args := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		line, err := readLine(r)
+		if err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		if len(line) == 0 || line[0] != '$' {
+			return nil, errors.New("ERR Protocol error: expected '$'")
+		}
+		size, err := strconv.Atoi(line[1:])
+		if err != nil || size < 0 || size > maxBulkBytes {
+			return nil, errors.New("ERR Protocol error: invalid bulk length")
+		}
+		bulk := make([]byte, size+2)
+		if _, err := io.ReadFull(r, bulk); err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		if bulk[size] != '\r' || bulk[size+1] != '\n' {
+			return nil, errors.New("ERR Protocol error: bulk string not terminated by CRLF")
+		}
+		args = append(args, string(bulk[:size]))
+	}
+	return args, nil
+}
+
+// Reads a line terminated by CRLF, or by LF alone for inline commands.
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadSlice('\n')
+	if err == bufio.ErrBufferFull {
+		return "", errors.New("ERR Protocol error: line too long")
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(string(line[:len(line)-1]), "\r"), nil
+}
+
+// Once a command has started, running out of input is never a clean EOF.
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+func writeBulk(w *bufio.Writer, s string) {
+	fmt.Fprintf(w, "$%d\r\n%s\r\n", len(s), s)
+}
+
+func writeBool(w *bufio.Writer, b bool) {
+	if b {
+		w.WriteString(":1\r\n")
+	} else {
+		w.WriteString(":0\r\n")
+	}
+}
+
+// Writes an error reply. Error replies cannot span lines.
+func writeError(w *bufio.Writer, message string) {
+	message = strings.NewReplacer("\r", " ", "\n", " ").Replace(message)
+	w.WriteString("-" + message + "\r\n")
+}
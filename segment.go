@@ -0,0 +1,209 @@
+package tripod
+
+// Represents the strategy a Segmenter uses to split a text into the words
+// stored in its PrefixStoreRuneTrie.
+type SegmentMode int
+
+const (
+	// Greedily takes the longest stored word starting at the beginning of
+	// the text, then repeats from the end of that word.
+	SegmentForward SegmentMode = iota
+
+	// Greedily takes the longest stored word ending at the end of the text,
+	// then repeats from the start of that word.
+	SegmentBackward
+
+	// Picks, among all the segmentations of the text, the one with the
+	// fewest tokens, or with the highest total weight when the Segmenter
+	// has a weight function.
+	SegmentBest
+)
+
+// Represents a token of a segmented text: text[Start:End], in runes. Known
+// is false for a rune that does not start (or end, for SegmentBackward) any
+// stored word, and is hence emitted as a token on its own.
+type Token struct {
+	Start int
+	End   int
+	Known bool
+}
+
+// Represents a dictionary-based segmenter that splits texts written without
+// separators, like CJK text or hashtags, into the words stored in a
+// PrefixStoreRuneTrie.
+// A Segmenter reuses internal buffers across calls, hence it must not be
+// used by multiple goroutines concurrently.
+type Segmenter struct {
+	trie   *PrefixStoreRuneTrie
+	mode   SegmentMode
+	weight func(word []rune) float64
+
+	// Buffers for SegmentBest, indexed by the rune offset in the text.
+	unknowns []int
+	scores   []float64
+	starts   []int
+}
+
+// Creates and returns reference to a new instance of Segmenter over the words
+// stored in t. weight is only used by SegmentBest and may be nil, in which
+// case the segmentation with the fewest tokens is picked; otherwise the one
+// maximizing the sum of weight over the known tokens is picked. weight is
+// called with a subslice of the text, which it must not retain.
+func CreateSegmenter(t *PrefixStoreRuneTrie, mode SegmentMode, weight func(word []rune) float64) *Segmenter {
+	return &Segmenter{
+		trie:   t,
+		mode:   mode,
+		weight: weight,
+	}
+}
+
+// Segments text and appends its tokens, in order, to dst and returns the
+// extended slice. No allocation is made when dst has enough capacity.
+func (s *Segmenter) Segment(dst []Token, text []rune) []Token {
+	switch s.mode {
+	case SegmentBackward:
+		return s.segmentBackward(dst, text)
+	case SegmentBest:
+		return s.segmentBest(dst, text)
+	default:
+		return s.segmentForward(dst, text)
+	}
+}
+
+// Returns the length of the longest stored word that is a prefix of text, or
+// 0 if there is none.
+func (s *Segmenter) longestWordAt(text []rune) int {
+	longest := 0
+	current_node := s.trie
+	for i, r := range text {
+		if i == s.trie.maxKeySizeInRunes {
+			break
+		}
+		current_node = current_node.children[r]
+		if current_node == nil {
+			break
+		}
+		if current_node.isLast {
+			longest = i + 1
+		}
+	}
+	return longest
+}
+
+func (s *Segmenter) segmentForward(dst []Token, text []rune) []Token {
+	for start := 0; start < len(text); {
+		size := s.longestWordAt(text[start:])
+		known := size > 0
+		if !known {
+			size = 1
+		}
+		dst = append(dst, Token{Start: start, End: start + size, Known: known})
+		start += size
+	}
+	return dst
+}
+
+func (s *Segmenter) segmentBackward(dst []Token, text []rune) []Token {
+	first := len(dst)
+	for end := len(text); end > 0; {
+		// The candidates are tried from the longest, and the trie is only
+		// searched forward, hence each one is looked up on its own.
+		start := end - 1
+		known := false
+		for candidate := max(0, end-s.trie.maxKeySizeInRunes); candidate < end; candidate++ {
+			if s.trie.Exists(text[candidate:end]) {
+				start = candidate
+				known = true
+				break
+			}
+		}
+		dst = append(dst, Token{Start: start, End: end, Known: known})
+		end = start
+	}
+	reverseTokens(dst[first:])
+	return dst
+}
+
+// Segments text via dynamic programming over its rune offsets. A
+// segmentation with fewer unknown tokens is always preferred; among those,
+// the one with the highest score, i.e. the fewest tokens or the highest
+// total weight, is picked.
+func (s *Segmenter) segmentBest(dst []Token, text []rune) []Token {
+	n := len(text)
+	s.unknowns = resizeInts(s.unknowns, n+1)
+	s.scores = resizeFloats(s.scores, n+1)
+	s.starts = resizeInts(s.starts, n+1)
+
+	for i := 1; i <= n; i++ {
+		s.unknowns[i] = -1
+	}
+	s.unknowns[0] = 0
+	s.scores[0] = 0
+
+	relax := func(start, end, unknowns int, score float64) {
+		if s.unknowns[end] < 0 || unknowns < s.unknowns[end] ||
+			(unknowns == s.unknowns[end] && score > s.scores[end]) {
+			s.unknowns[end] = unknowns
+			s.scores[end] = score
+			s.starts[end] = start
+		}
+	}
+
+	for start := 0; start < n; start++ {
+		unknowns, score := s.unknowns[start], s.scores[start]
+		if s.weight == nil {
+			// Unknown tokens also count towards the fewest tokens.
+			relax(start, start+1, unknowns+1, score-1)
+		} else {
+			relax(start, start+1, unknowns+1, score)
+		}
+
+		current_node := s.trie
+		for end := start; end < n && end-start < s.trie.maxKeySizeInRunes; end++ {
+			current_node = current_node.children[text[end]]
+			if current_node == nil {
+				break
+			}
+			if !current_node.isLast {
+				continue
+			}
+			if s.weight == nil {
+				relax(start, end+1, unknowns, score-1)
+			} else {
+				relax(start, end+1, unknowns, score+s.weight(text[start:end+1]))
+			}
+		}
+	}
+
+	first := len(dst)
+	for end := n; end > 0; {
+		start := s.starts[end]
+		// A single rune is a known token only if it is a stored word that
+		// was picked as such, i.e. if it added no unknown.
+		known := s.unknowns[start] == s.unknowns[end]
+		dst = append(dst, Token{Start: start, End: end, Known: known})
+		end = start
+	}
+	reverseTokens(dst[first:])
+	return dst
+}
+
+func reverseTokens(tokens []Token) {
+	for i, j := 0, len(tokens)-1; i < j; i, j = i+1, j-1 {
+		tokens[i], tokens[j] = tokens[j], tokens[i]
+	}
+}
+
+func resizeInts(buffer []int, size int) []int {
+	if cap(buffer) < size {
+		return make([]int, size)
+	}
+	return buffer[:size]
+}
+
+func resizeFloats(buffer []float64, size int) []float64 {
+	if cap(buffer) < size {
+		return make([]float64, size)
+	}
+	return buffer[:size]
+}
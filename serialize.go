@@ -0,0 +1,251 @@
+package tripod
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Every serialized PrefixStore starts with magic, followed by a byte denoting
+// the kind of the store and a byte denoting the version of the format.
+// The header is followed by maxKeySize as uvarint and then all the keys in
+// ascending order, each as uvarint length followed by its elements. Since
+// empty keys cannot be stored, a zero length marks the end of the keys.
+const (
+	serializedMagic     = "TRIPOD"
+	serializedVersion   = 1
+	serializedKindBytes = 'b'
+	serializedKindRunes = 'r'
+)
+
+// Largest maxKeySize a serialized PrefixStore can have. The readers allocate
+// buffers of maxKeySize, which comes from untrusted input, hence it is
+// bounded.
+const maxSerializedKeySize = 1 << 20
+
+// Returned when reading a serialized PrefixStore of one kind as a
+// PrefixStore of another kind, e.g. a PrefixStoreRuneTrie as a
+// PrefixStoreByteTrie.
+var ErrStoreKindMismatch = errors.New("serialized store is of a different kind")
+
+// Serializes the PrefixStore to w and returns the number of bytes written and
+// any error encountered. The keys are written in ascending order, hence the
+// output can be read back in a single linear pass by
+// ReadPrefixStoreByteTrie.
+// A non nil error is returned if maxKeySize > 1<<20, as such a store could
+// not be read back.
+func (t *PrefixStoreByteTrie) WriteTo(w io.Writer) (int64, error) {
+	if err := checkSerializedKeySize(t.maxKeySizeInBytes); err != nil {
+		return 0, err
+	}
+	cw := &countingWriter{w: bufio.NewWriter(w)}
+	writeHeader(cw, serializedKindBytes, t.maxKeySizeInBytes)
+
+	buffer := make([]byte, 0, t.maxKeySizeInBytes)
+	var scratch [binary.MaxVarintLen64]byte
+	t.walkSorted(buffer, func(key []byte) {
+		cw.Write(scratch[:binary.PutUvarint(scratch[:], uint64(len(key)))])
+		cw.Write(key)
+	})
+	cw.Write(scratch[:binary.PutUvarint(scratch[:], 0)])
+
+	if cw.err == nil {
+		cw.err = cw.w.(*bufio.Writer).Flush()
+	}
+	return cw.n, cw.err
+}
+
+// Serializes the PrefixStore to w and returns the number of bytes written and
+// any error encountered. The keys are written in ascending order, hence the
+// output can be read back in a single linear pass by
+// ReadPrefixStoreRuneTrie.
+// A non nil error is returned if maxKeySize > 1<<20, as such a store could
+// not be read back.
+func (t *PrefixStoreRuneTrie) WriteTo(w io.Writer) (int64, error) {
+	if err := checkSerializedKeySize(t.maxKeySizeInRunes); err != nil {
+		return 0, err
+	}
+	cw := &countingWriter{w: bufio.NewWriter(w)}
+	writeHeader(cw, serializedKindRunes, t.maxKeySizeInRunes)
+
+	buffer := make([]rune, 0, t.maxKeySizeInRunes)
+	var scratch [binary.MaxVarintLen64]byte
+	t.walkSorted(buffer, func(key []rune) {
+		cw.Write(scratch[:binary.PutUvarint(scratch[:], uint64(len(key)))])
+		for _, r := range key {
+			cw.Write(scratch[:binary.PutUvarint(scratch[:], uint64(uint32(r)))])
+		}
+	})
+	cw.Write(scratch[:binary.PutUvarint(scratch[:], 0)])
+
+	if cw.err == nil {
+		cw.err = cw.w.(*bufio.Writer).Flush()
+	}
+	return cw.n, cw.err
+}
+
+// Reads a PrefixStoreByteTrie serialized by PrefixStoreByteTrie.WriteTo and
+// returns reference to it. ErrStoreKindMismatch is returned if r holds a
+// different kind of PrefixStore.
+func ReadPrefixStoreByteTrie(r io.Reader) (*PrefixStoreByteTrie, error) {
+	br := bufio.NewReader(r)
+	maxKeySizeInBytes, err := readHeader(br, serializedKindBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	var readErr error
+	buffer := make([]byte, 0, maxKeySizeInBytes)
+	t, err := BuildPrefixStoreByteTrieFromSorted(maxKeySizeInBytes, func() ([]byte, bool) {
+		var length int
+		if length, readErr = readLength(br, maxKeySizeInBytes); readErr != nil || length == 0 {
+			return nil, false
+		}
+		buffer = buffer[:length]
+		if _, readErr = io.ReadFull(br, buffer); readErr != nil {
+			return nil, false
+		}
+		return buffer, true
+	})
+	if readErr != nil {
+		return nil, unexpectedEOF(readErr)
+	}
+	return t, err
+}
+
+// Reads a PrefixStoreRuneTrie serialized by PrefixStoreRuneTrie.WriteTo and
+// returns reference to it. ErrStoreKindMismatch is returned if r holds a
+// different kind of PrefixStore.
+func ReadPrefixStoreRuneTrie(r io.Reader) (*PrefixStoreRuneTrie, error) {
+	br := bufio.NewReader(r)
+	maxKeySizeInRunes, err := readHeader(br, serializedKindRunes)
+	if err != nil {
+		return nil, err
+	}
+
+	var readErr error
+	buffer := make([]rune, 0, maxKeySizeInRunes)
+	t, err := BuildPrefixStoreRuneTrieFromSorted(maxKeySizeInRunes, func() ([]rune, bool) {
+		var length int
+		if length, readErr = readLength(br, maxKeySizeInRunes); readErr != nil || length == 0 {
+			return nil, false
+		}
+		buffer = buffer[:0]
+		for i := 0; i < length; i++ {
+			var r uint64
+			if r, readErr = binary.ReadUvarint(br); readErr != nil {
+				return nil, false
+			}
+			buffer = append(buffer, rune(uint32(r)))
+		}
+		return buffer, true
+	})
+	if readErr != nil {
+		return nil, unexpectedEOF(readErr)
+	}
+	return t, err
+}
+
+// Reads a PrefixStore of either kind serialized by WriteTo and returns
+// reference to it: exactly one of the returned tries is non nil unless an
+// error is returned. The kind is told from the header, hence r is read only
+// once.
+func ReadPrefixStore(r io.Reader) (*PrefixStoreByteTrie, *PrefixStoreRuneTrie, error) {
+	br := bufio.NewReader(r)
+	header, err := br.Peek(len(serializedMagic) + 1)
+	if err != nil {
+		return nil, nil, unexpectedEOF(err)
+	}
+	if string(header[:len(serializedMagic)]) == serializedMagic && header[len(serializedMagic)] == serializedKindRunes {
+		runes, err := ReadPrefixStoreRuneTrie(br)
+		return nil, runes, err
+	}
+	bytes, err := ReadPrefixStoreByteTrie(br)
+	return bytes, nil, err
+}
+
+// Writes magic, kind, version and maxKeySize to w.
+func writeHeader(w io.Writer, kind byte, maxKeySize int) {
+	var scratch [binary.MaxVarintLen64]byte
+	w.Write([]byte(serializedMagic))
+	w.Write([]byte{kind, serializedVersion})
+	w.Write(scratch[:binary.PutUvarint(scratch[:], uint64(maxKeySize))])
+}
+
+// Reads and validates the header written by writeHeader and returns the
+// maxKeySize of the serialized store.
+func readHeader(br *bufio.Reader, kind byte) (int, error) {
+	header := make([]byte, len(serializedMagic)+2)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return 0, unexpectedEOF(err)
+	}
+	if string(header[:len(serializedMagic)]) != serializedMagic {
+		return 0, fmt.Errorf("not a serialized prefix store")
+	}
+	if header[len(serializedMagic)] != kind {
+		return 0, ErrStoreKindMismatch
+	}
+	if version := header[len(serializedMagic)+1]; version != serializedVersion {
+		return 0, fmt.Errorf("unsupported serialization version %d", version)
+	}
+
+	maxKeySize, err := binary.ReadUvarint(br)
+	if err != nil {
+		return 0, unexpectedEOF(err)
+	}
+	if maxKeySize > maxSerializedKeySize {
+		return 0, fmt.Errorf("corrupt max size of key %d (> %d)", maxKeySize, maxSerializedKeySize)
+	}
+	return int(maxKeySize), nil
+}
+
+// Checks that a PrefixStore with given maxKeySize can be read back once
+// serialized.
+func checkSerializedKeySize(maxKeySize int) error {
+	if maxKeySize < 0 || maxKeySize > maxSerializedKeySize {
+		return fmt.Errorf("max size of key should be at most %d to serialize (%d > %d)",
+			maxSerializedKeySize, maxKeySize, maxSerializedKeySize)
+	}
+	return nil
+}
+
+// Reads the length of the next key and validates it against maxKeySize.
+func readLength(br *bufio.Reader, maxKeySize int) (int, error) {
+	length, err := binary.ReadUvarint(br)
+	if err != nil {
+		return 0, err
+	}
+	if length > uint64(maxKeySize) {
+		return 0, fmt.Errorf("corrupt key length %d (> %d)", length, maxKeySize)
+	}
+	return int(length), nil
+}
+
+// A serialized store always ends with the terminating zero length, hence
+// running out of input midway is never a clean EOF.
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// Writer that counts the bytes written and remembers the first error, so that
+// the serialization need not check errors on every write.
+type countingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	if cw.err != nil {
+		return 0, cw.err
+	}
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	cw.err = err
+	return n, err
+}
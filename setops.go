@@ -0,0 +1,215 @@
+package tripod
+
+// Set operations between two tries, computed by walking both in lockstep:
+// the keys shared by the two tries share their path, hence the result is
+// built node by node without enumerating the keys of either trie. The result
+// is a new trie; the operands are left untouched and share no node with it.
+
+type setOperation int
+
+const (
+	setUnion setOperation = iota
+	setIntersection
+	setDifference
+)
+
+// Returns if a key present in a (or not) and in b (or not) is in the result
+// of op.
+func (op setOperation) keeps(inA, inB bool) bool {
+	switch op {
+	case setUnion:
+		return inA || inB
+	case setIntersection:
+		return inA && inB
+	default:
+		return inA && !inB
+	}
+}
+
+// Creates and returns reference to a new instance of PrefixStoreByteTrie
+// holding the keys present in a or in b. The maximum key size of the result
+// is the greater of the two.
+func UnionByteTries(a, b *PrefixStoreByteTrie) *PrefixStoreByteTrie {
+	return combineByteTries(a, b, setUnion, max(a.maxKeySizeInBytes, b.maxKeySizeInBytes))
+}
+
+// Creates and returns reference to a new instance of PrefixStoreByteTrie
+// holding the keys present in both a and b. The maximum key size of the
+// result is the smaller of the two.
+func IntersectByteTries(a, b *PrefixStoreByteTrie) *PrefixStoreByteTrie {
+	return combineByteTries(a, b, setIntersection, min(a.maxKeySizeInBytes, b.maxKeySizeInBytes))
+}
+
+// Creates and returns reference to a new instance of PrefixStoreByteTrie
+// holding the keys present in a but not in b. The maximum key size of the
+// result is the one of a.
+func DifferenceByteTries(a, b *PrefixStoreByteTrie) *PrefixStoreByteTrie {
+	return combineByteTries(a, b, setDifference, a.maxKeySizeInBytes)
+}
+
+func combineByteTries(a, b *PrefixStoreByteTrie, op setOperation, maxKeySizeInBytes int) *PrefixStoreByteTrie {
+	t := combineByteNodes(a, b, op, maxKeySizeInBytes)
+	if t == nil {
+		return CreatePrefixStoreByteTrie(maxKeySizeInBytes)
+	}
+	if t.children == nil {
+		t.children = make(map[int]*PrefixStoreByteTrie)
+	}
+	return t
+}
+
+// Recursively returns the node holding the result of op on the keys under a
+// and b, either of which may be nil, or nil if the result has no key.
+func combineByteNodes(a, b *PrefixStoreByteTrie, op setOperation, maxKeySizeInBytes int) *PrefixStoreByteTrie {
+	if a == nil && b == nil {
+		return nil
+	}
+	// A path missing from either trie has no key of their intersection.
+	if op == setIntersection && (a == nil || b == nil) {
+		return nil
+	}
+	if op == setIntersection && len(b.children) < len(a.children) {
+		// Walks the smaller of the children maps.
+		a, b = b, a
+	}
+	if op == setDifference && a == nil {
+		return nil
+	}
+
+	t := &PrefixStoreByteTrie{
+		isLast:            op.keeps(a != nil && a.isLast, b != nil && b.isLast),
+		maxKeySizeInBytes: maxKeySizeInBytes,
+		nodes:             1,
+	}
+	if t.isLast {
+		t.count = 1
+	}
+
+	add := func(ch int, child *PrefixStoreByteTrie) {
+		if child == nil {
+			return
+		}
+		if t.children == nil {
+			t.children = make(map[int]*PrefixStoreByteTrie)
+		}
+		t.children[ch] = child
+		t.count += child.count
+		t.nodes += child.nodes
+	}
+
+	if a != nil {
+		for ch, childA := range a.children {
+			var childB *PrefixStoreByteTrie
+			if b != nil {
+				childB = b.children[ch]
+			}
+			add(ch, combineByteNodes(childA, childB, op, maxKeySizeInBytes))
+		}
+	}
+	if b != nil && op == setUnion {
+		for ch, childB := range b.children {
+			if a == nil || a.children[ch] == nil {
+				add(ch, combineByteNodes(nil, childB, op, maxKeySizeInBytes))
+			}
+		}
+	}
+
+	if t.count == 0 {
+		return nil
+	}
+	return t
+}
+
+// Creates and returns reference to a new instance of PrefixStoreRuneTrie
+// holding the keys present in a or in b. The maximum key size of the result
+// is the greater of the two.
+func UnionRuneTries(a, b *PrefixStoreRuneTrie) *PrefixStoreRuneTrie {
+	return combineRuneTries(a, b, setUnion, max(a.maxKeySizeInRunes, b.maxKeySizeInRunes))
+}
+
+// Creates and returns reference to a new instance of PrefixStoreRuneTrie
+// holding the keys present in both a and b. The maximum key size of the
+// result is the smaller of the two.
+func IntersectRuneTries(a, b *PrefixStoreRuneTrie) *PrefixStoreRuneTrie {
+	return combineRuneTries(a, b, setIntersection, min(a.maxKeySizeInRunes, b.maxKeySizeInRunes))
+}
+
+// Creates and returns reference to a new instance of PrefixStoreRuneTrie
+// holding the keys present in a but not in b. The maximum key size of the
+// result is the one of a.
+func DifferenceRuneTries(a, b *PrefixStoreRuneTrie) *PrefixStoreRuneTrie {
+	return combineRuneTries(a, b, setDifference, a.maxKeySizeInRunes)
+}
+
+func combineRuneTries(a, b *PrefixStoreRuneTrie, op setOperation, maxKeySizeInRunes int) *PrefixStoreRuneTrie {
+	t := combineRuneNodes(a, b, op, maxKeySizeInRunes)
+	if t == nil {
+		return CreatePrefixStoreRuneTrie(maxKeySizeInRunes)
+	}
+	if t.children == nil {
+		t.children = make(map[rune]*PrefixStoreRuneTrie)
+	}
+	return t
+}
+
+// Recursively returns the node holding the result of op on the keys under a
+// and b, either of which may be nil, or nil if the result has no key.
+func combineRuneNodes(a, b *PrefixStoreRuneTrie, op setOperation, maxKeySizeInRunes int) *PrefixStoreRuneTrie {
+	if a == nil && b == nil {
+		return nil
+	}
+	// A path missing from either trie has no key of their intersection.
+	if op == setIntersection && (a == nil || b == nil) {
+		return nil
+	}
+	if op == setIntersection && len(b.children) < len(a.children) {
+		// Walks the smaller of the children maps.
+		a, b = b, a
+	}
+	if op == setDifference && a == nil {
+		return nil
+	}
+
+	t := &PrefixStoreRuneTrie{
+		isLast:            op.keeps(a != nil && a.isLast, b != nil && b.isLast),
+		maxKeySizeInRunes: maxKeySizeInRunes,
+		nodes:             1,
+	}
+	if t.isLast {
+		t.count = 1
+	}
+
+	add := func(ch rune, child *PrefixStoreRuneTrie) {
+		if child == nil {
+			return
+		}
+		if t.children == nil {
+			t.children = make(map[rune]*PrefixStoreRuneTrie)
+		}
+		t.children[ch] = child
+		t.count += child.count
+		t.nodes += child.nodes
+	}
+
+	if a != nil {
+		for ch, childA := range a.children {
+			var childB *PrefixStoreRuneTrie
+			if b != nil {
+				childB = b.children[ch]
+			}
+			add(ch, combineRuneNodes(childA, childB, op, maxKeySizeInRunes))
+		}
+	}
+	if b != nil && op == setUnion {
+		for ch, childB := range b.children {
+			if a == nil || a.children[ch] == nil {
+				add(ch, combineRuneNodes(nil, childB, op, maxKeySizeInRunes))
+			}
+		}
+	}
+
+	if t.count == 0 {
+		return nil
+	}
+	return t
+}
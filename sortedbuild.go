@@ -0,0 +1,149 @@
+package tripod
+
+import (
+	"fmt"
+)
+
+// Iterates over []byte keys. Each call returns the next key and true, or
+// false once the keys are exhausted. The returned key is only read until the
+// following call, hence the iterator is free to reuse its buffer.
+type ByteKeyIterator func() ([]byte, bool)
+
+// Iterates over []rune keys. Each call returns the next key and true, or
+// false once the keys are exhausted. The returned key is only read until the
+// following call, hence the iterator is free to reuse its buffer.
+type RuneKeyIterator func() ([]rune, bool)
+
+// Creates and returns reference to a new instance of PrefixStoreByteTrie
+// holding every key yielded by next. The keys must be sorted in ascending
+// byte-wise order; duplicates and empty keys are ignored.
+// Since consecutive sorted keys share their common prefix, the trie is built
+// in a single linear pass without walking from the root for every key.
+// A non nil error is returned if the keys are not sorted or if a key is
+// longer than maxKeySizeInBytes.
+func BuildPrefixStoreByteTrieFromSorted(maxKeySizeInBytes int, next ByteKeyIterator) (*PrefixStoreByteTrie, error) {
+	t := CreatePrefixStoreByteTrie(maxKeySizeInBytes)
+
+	// path[i] is the node reached after the first i bytes of the previous
+	// key, and prev is a copy of the previous key. Both grow with the keys
+	// rather than with maxKeySizeInBytes, which may be huge, or negative.
+	path := []*PrefixStoreByteTrie{t}
+	var prev []byte
+
+	for index := 0; ; index++ {
+		key, ok := next()
+		if !ok {
+			return t, nil
+		}
+		if len(key) > maxKeySizeInBytes {
+			return nil, fmt.Errorf("max size of key should be %d (%d > %d) at index %d",
+				maxKeySizeInBytes, len(key), maxKeySizeInBytes, index)
+		}
+		if len(key) == 0 {
+			continue
+		}
+
+		lcp := 0
+		for lcp < len(prev) && lcp < len(key) && prev[lcp] == key[lcp] {
+			lcp++
+		}
+		if lcp < len(prev) && (lcp == len(key) || key[lcp] < prev[lcp]) {
+			return nil, fmt.Errorf("keys are not sorted: %q comes after %q at index %d",
+				key, prev, index)
+		}
+		if lcp == len(key) {
+			// Same as the previous key.
+			continue
+		}
+
+		// Being sorted, no key seen so far extends key[:lcp+1], hence every
+		// node below path[lcp] is new. Every node is allocated on its own,
+		// so that a node pruned by Delete can be freed, and the last one,
+		// being a leaf, gets its children map lazily on a later Put.
+		path = path[:lcp+1]
+		current_node := path[lcp]
+		for _, b := range key[lcp:] {
+			child := &PrefixStoreByteTrie{maxKeySizeInBytes: maxKeySizeInBytes, nodes: 1}
+			if current_node.children == nil {
+				current_node.children = make(map[int]*PrefixStoreByteTrie)
+			}
+			current_node.children[int(b)] = child
+			current_node = child
+			path = append(path, current_node)
+		}
+		current_node.isLast = true
+		for i, node := range path {
+			node.count++
+			node.nodes += min(len(key)-lcp, len(key)-i)
+		}
+
+		prev = append(prev[:0], key...)
+	}
+}
+
+// Creates and returns reference to a new instance of PrefixStoreRuneTrie
+// holding every key yielded by next. The keys must be sorted in ascending
+// rune-wise order; duplicates and empty keys are ignored.
+// Since consecutive sorted keys share their common prefix, the trie is built
+// in a single linear pass without walking from the root for every key.
+// A non nil error is returned if the keys are not sorted or if a key is
+// longer than maxKeySizeInRunes.
+func BuildPrefixStoreRuneTrieFromSorted(maxKeySizeInRunes int, next RuneKeyIterator) (*PrefixStoreRuneTrie, error) {
+	t := CreatePrefixStoreRuneTrie(maxKeySizeInRunes)
+
+	// path[i] is the node reached after the first i runes of the previous
+	// key, and prev is a copy of the previous key. Both grow with the keys
+	// rather than with maxKeySizeInRunes, which may be huge, or negative.
+	path := []*PrefixStoreRuneTrie{t}
+	var prev []rune
+
+	for index := 0; ; index++ {
+		key, ok := next()
+		if !ok {
+			return t, nil
+		}
+		if len(key) > maxKeySizeInRunes {
+			return nil, fmt.Errorf("max size of key should be %d (%d > %d) at index %d",
+				maxKeySizeInRunes, len(key), maxKeySizeInRunes, index)
+		}
+		if len(key) == 0 {
+			continue
+		}
+
+		lcp := 0
+		for lcp < len(prev) && lcp < len(key) && prev[lcp] == key[lcp] {
+			lcp++
+		}
+		if lcp < len(prev) && (lcp == len(key) || key[lcp] < prev[lcp]) {
+			return nil, fmt.Errorf("keys are not sorted: %q comes after %q at index %d",
+				string(key), string(prev), index)
+		}
+		if lcp == len(key) {
+			// Same as the previous key.
+			continue
+		}
+
+		// Being sorted, no key seen so far extends key[:lcp+1], hence every
+		// node below path[lcp] is new. Every node is allocated on its own,
+		// so that a node pruned by Delete can be freed, and the last one,
+		// being a leaf, gets its children map lazily on a later Put.
+		path = path[:lcp+1]
+		current_node := path[lcp]
+		for _, r := range key[lcp:] {
+			child := &PrefixStoreRuneTrie{maxKeySizeInRunes: maxKeySizeInRunes, nodes: 1}
+			if current_node.children == nil {
+				current_node.children = make(map[rune]*PrefixStoreRuneTrie)
+			}
+			current_node.children[r] = child
+			current_node = child
+			path = append(path, current_node)
+		}
+		current_node.isLast = true
+		for i, node := range path {
+			node.count++
+			node.nodes += min(len(key)-lcp, len(key)-i)
+		}
+
+		prev = append(prev[:0], key...)
+	}
+}
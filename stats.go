@@ -0,0 +1,175 @@
+package tripod
+
+import (
+	"unsafe"
+)
+
+// Structural statistics of a PrefixStore.
+type PrefixStoreStats struct {
+	// Number of keys stored.
+	KeyCount int
+
+	// Number of trie nodes, including the root.
+	NodeCount int
+
+	// Length of the longest key, and average length of the keys.
+	MaxDepth int
+	AvgDepth float64
+
+	// Number of nodes by number of children: ChildrenHistogram[i] is the
+	// number of nodes having i children. Only the numbers of children some
+	// node has are present.
+	ChildrenHistogram map[int]int
+
+	// Estimated number of bytes held by the nodes, by their children maps,
+	// and by both.
+	NodeBytes      int64
+	MapBytes       int64
+	EstimatedBytes int64
+
+	keyDepthSum int64
+}
+
+// Summary of a PrefixStore, kept up to date by the methods adding or removing
+// keys, hence returned in constant time.
+type PrefixStoreSummary struct {
+	// Number of keys stored.
+	KeyCount int
+
+	// Number of trie nodes, including the root.
+	NodeCount int
+
+	// Rough number of bytes held by the nodes, counting every node as its
+	// own size, an empty children map and its slot in the children map of
+	// its parent. Unlike the estimate of Stats, it ignores the growth of the
+	// children maps.
+	EstimatedBytes int64
+}
+
+// Rough number of bytes held by a trie node: the node, its empty children
+// map, and its slot in the children map of its parent.
+var (
+	byteTrieNodeBytes = int64(unsafe.Sizeof(PrefixStoreByteTrie{})) + mapHeaderBytes +
+		int64(unsafe.Sizeof(int(0))+unsafe.Sizeof((*PrefixStoreByteTrie)(nil))+mapSlotCtrlBytes)
+	runeTrieNodeBytes = int64(unsafe.Sizeof(PrefixStoreRuneTrie{})) + mapHeaderBytes +
+		int64(unsafe.Sizeof(rune(0))+unsafe.Sizeof((*PrefixStoreRuneTrie)(nil))+mapSlotCtrlBytes)
+)
+
+// Accounts for a node at depth, with given number of children, taking
+// nodeBytes and mapBytes.
+func (stats *PrefixStoreStats) addNode(depth int, isLast bool, children int, nodeBytes, mapBytes int64) {
+	stats.NodeCount++
+	if isLast {
+		stats.KeyCount++
+		stats.keyDepthSum += int64(depth)
+		if depth > stats.MaxDepth {
+			stats.MaxDepth = depth
+		}
+	}
+
+	if stats.ChildrenHistogram == nil {
+		stats.ChildrenHistogram = make(map[int]int)
+	}
+	stats.ChildrenHistogram[children]++
+
+	stats.NodeBytes += nodeBytes
+	stats.MapBytes += mapBytes
+	stats.EstimatedBytes += nodeBytes + mapBytes
+}
+
+// Computes the statistics derived from the accounted nodes.
+func (stats *PrefixStoreStats) finish() {
+	if stats.KeyCount > 0 {
+		stats.AvgDepth = float64(stats.keyDepthSum) / float64(stats.KeyCount)
+	}
+}
+
+// Rough sizes used to estimate the memory held by a children map: a map
+// header, and per slot the key, the value and a byte of control information.
+// Maps keep their load factor under 7/8 and grow in powers of two.
+const (
+	mapHeaderBytes   = 48
+	mapMinSlots      = 8
+	mapSlotCtrlBytes = 1
+)
+
+// Estimates the bytes held by a map of given number of entries with keys and
+// values of given sizes.
+func estimateMapBytes(entries int, keySize, valueSize uintptr) int64 {
+	if entries == 0 {
+		return mapHeaderBytes
+	}
+	slots := mapMinSlots
+	for slots*7/8 < entries {
+		slots *= 2
+	}
+	return mapHeaderBytes + int64(slots)*int64(keySize+valueSize+mapSlotCtrlBytes)
+}
+
+// Returns the summary of the PrefixStore in constant time, hence cheap
+// enough to be exported periodically, unlike Stats.
+func (t *PrefixStoreByteTrie) Summary() PrefixStoreSummary {
+	return PrefixStoreSummary{
+		KeyCount:       t.count,
+		NodeCount:      t.nodes,
+		EstimatedBytes: int64(t.nodes) * byteTrieNodeBytes,
+	}
+}
+
+// Traverses the whole PrefixStore and returns its structural statistics.
+// Nothing is cached, hence every call visits every node and takes time
+// proportional to the number of nodes; Summary returns the key and node
+// counts in constant time.
+func (t *PrefixStoreByteTrie) Stats() PrefixStoreStats {
+	var stats PrefixStoreStats
+	t.collectStats(&stats, 0)
+	stats.finish()
+	return stats
+}
+
+func (t *PrefixStoreByteTrie) collectStats(stats *PrefixStoreStats, depth int) {
+	var mapBytes int64
+	if t.children != nil {
+		mapBytes = estimateMapBytes(len(t.children),
+			unsafe.Sizeof(int(0)), unsafe.Sizeof(t))
+	}
+	stats.addNode(depth, t.isLast, len(t.children), int64(unsafe.Sizeof(*t)), mapBytes)
+
+	for _, tt := range t.children {
+		tt.collectStats(stats, depth+1)
+	}
+}
+
+// Returns the summary of the PrefixStore in constant time, hence cheap
+// enough to be exported periodically, unlike Stats.
+func (t *PrefixStoreRuneTrie) Summary() PrefixStoreSummary {
+	return PrefixStoreSummary{
+		KeyCount:       t.count,
+		NodeCount:      t.nodes,
+		EstimatedBytes: int64(t.nodes) * runeTrieNodeBytes,
+	}
+}
+
+// Traverses the whole PrefixStore and returns its structural statistics.
+// Nothing is cached, hence every call visits every node and takes time
+// proportional to the number of nodes; Summary returns the key and node
+// counts in constant time.
+func (t *PrefixStoreRuneTrie) Stats() PrefixStoreStats {
+	var stats PrefixStoreStats
+	t.collectStats(&stats, 0)
+	stats.finish()
+	return stats
+}
+
+func (t *PrefixStoreRuneTrie) collectStats(stats *PrefixStoreStats, depth int) {
+	var mapBytes int64
+	if t.children != nil {
+		mapBytes = estimateMapBytes(len(t.children),
+			unsafe.Sizeof(rune(0)), unsafe.Sizeof(t))
+	}
+	stats.addNode(depth, t.isLast, len(t.children), int64(unsafe.Sizeof(*t)), mapBytes)
+
+	for _, tt := range t.children {
+		tt.collectStats(stats, depth+1)
+	}
+}
@@ -0,0 +1,194 @@
+package tripod
+
+import (
+	"container/list"
+	"fmt"
+	"sort"
+)
+
+// Represents a store of []rune keys that can be searched by any fragment of
+// the keys, not only by their prefix. It is implemented via a generalized
+// suffix trie: every suffix of every key is put in a trie, hence the keys
+// containing a fragment are the ones having a suffix that starts with it.
+// The suffix trie takes memory quadratic in the size of the keys, which is
+// bounded by maxKeySizeInRunes.
+type SubstringStore struct {
+	suffixes          *suffixTrieNode
+	keys              []substringKey // by id
+	ids               map[string]int // id by key
+	maxKeySizeInRunes int
+
+	// Ids of deleted keys, reused by the keys put next, so that the keys do
+	// not grow under puts and deletes, and the sequence number of the next
+	// key put.
+	freeIDs []int
+	nextSeq uint64
+}
+
+// A key of a SubstringStore along with its sequence number, which orders the
+// keys as they were put since ids are reused.
+type substringKey struct {
+	runes []rune // nil once deleted
+	seq   uint64
+}
+
+// A node of the suffix trie. keyIDs holds the ids of the keys having the path
+// from the root to the node as a suffix. children is only allocated when the
+// node has children.
+type suffixTrieNode struct {
+	children map[rune]*suffixTrieNode
+	keyIDs   []int
+}
+
+// Creates and returns reference to a new instance of SubstringStore.
+// maxKeySizeInRunes is the maximum size of the key ([]rune) that should be
+// allowed to be added to the store. When tried to put key of length more
+// than maxKeySizeInRunes, the method will return the error.
+func CreateSubstringStore(maxKeySizeInRunes int) *SubstringStore {
+	return &SubstringStore{
+		suffixes:          &suffixTrieNode{},
+		ids:               make(map[string]int),
+		maxKeySizeInRunes: maxKeySizeInRunes,
+	}
+}
+
+// Adds the []rune key and all its suffixes to the store and returns if key
+// was succesfully added and any error encountered.
+// A non nil error is returned if len(key) > maxKeySizeInRunes
+func (s *SubstringStore) Put(key []rune) (bool, error) {
+	if len(key) > s.maxKeySizeInRunes {
+		return false, fmt.Errorf("max size of key should be %d (%d > %d)",
+			s.maxKeySizeInRunes, len(key), s.maxKeySizeInRunes)
+	}
+	if len(key) == 0 {
+		return false, nil
+	}
+	if _, ok := s.ids[string(key)]; ok {
+		return false, nil
+	}
+
+	stored := make([]rune, len(key))
+	copy(stored, key)
+	var id int
+	if n := len(s.freeIDs); n > 0 {
+		id = s.freeIDs[n-1]
+		s.freeIDs = s.freeIDs[:n-1]
+		s.keys[id] = substringKey{runes: stored, seq: s.nextSeq}
+	} else {
+		id = len(s.keys)
+		s.keys = append(s.keys, substringKey{runes: stored, seq: s.nextSeq})
+	}
+	s.nextSeq++
+	s.ids[string(key)] = id
+
+	for start := range stored {
+		current_node := s.suffixes
+		for _, r := range stored[start:] {
+			child := current_node.children[r]
+			if child == nil {
+				child = &suffixTrieNode{}
+				if current_node.children == nil {
+					current_node.children = make(map[rune]*suffixTrieNode)
+				}
+				current_node.children[r] = child
+			}
+			current_node = child
+		}
+		current_node.keyIDs = append(current_node.keyIDs, id)
+	}
+	return true, nil
+}
+
+// Checks and returns if given key is present in the store.
+func (s *SubstringStore) Exists(key []rune) bool {
+	_, ok := s.ids[string(key)]
+	return ok
+}
+
+// Returns the number of keys present in the store.
+func (s *SubstringStore) Count() int {
+	return len(s.ids)
+}
+
+// Removes the key and its suffixes from the store and returns if the key was
+// present. Nodes that are left with no suffix under them are pruned.
+func (s *SubstringStore) Delete(key []rune) bool {
+	id, ok := s.ids[string(key)]
+	if !ok {
+		return false
+	}
+
+	stored := s.keys[id].runes
+	for start := range stored {
+		s.suffixes.deleteSuffix(stored[start:], id)
+	}
+	s.keys[id] = substringKey{}
+	s.freeIDs = append(s.freeIDs, id)
+	delete(s.ids, string(key))
+	return true
+}
+
+// Recursively removes id from the node at the end of suffix and returns if
+// n is left with no suffix under it, so that the parent can prune it.
+func (n *suffixTrieNode) deleteSuffix(suffix []rune, id int) bool {
+	if len(suffix) == 0 {
+		for i, keyID := range n.keyIDs {
+			if keyID == id {
+				n.keyIDs = append(n.keyIDs[:i], n.keyIDs[i+1:]...)
+				break
+			}
+		}
+	} else if child := n.children[suffix[0]]; child != nil && child.deleteSuffix(suffix[1:], id) {
+		delete(n.children, suffix[0])
+	}
+	return len(n.keyIDs) == 0 && len(n.children) == 0
+}
+
+// Searches the store for the fragment and returns a reference to list
+// (*list.List) containing every key that contains the fragment, each key
+// once, in the order they were put. Each element of the list is []rune.
+func (s *SubstringStore) ContainsSearch(fragment []rune) *list.List {
+	entries := list.New()
+	if len(fragment) > s.maxKeySizeInRunes {
+		return entries
+	}
+
+	current_node := s.suffixes
+	for _, r := range fragment {
+		current_node = current_node.children[r]
+		if current_node == nil {
+			return entries
+		}
+	}
+
+	// A key has one suffix per occurrence of the fragment, hence the ids
+	// are deduplicated.
+	seen := make(map[int]bool)
+	var ids []int
+	current_node.collect(func(id int) {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	})
+	sort.Slice(ids, func(i, j int) bool {
+		return s.keys[ids[i]].seq < s.keys[ids[j]].seq
+	})
+
+	for _, id := range ids {
+		key := make([]rune, len(s.keys[id].runes))
+		copy(key, s.keys[id].runes)
+		entries.PushBack(key)
+	}
+	return entries
+}
+
+// Calls fn with the key ids of every suffix under n.
+func (n *suffixTrieNode) collect(fn func(id int)) {
+	for _, id := range n.keyIDs {
+		fn(id)
+	}
+	for _, child := range n.children {
+		child.collect(fn)
+	}
+}
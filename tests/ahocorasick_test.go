@@ -0,0 +1,84 @@
+package test_tripod
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/arpitbbhayani/tripod"
+	"testing"
+)
+
+func findAll(a *tripod.AhoCorasickByteTrie, text string) []string {
+	var found []string
+	a.FindAll([]byte(text), func(m tripod.Match) bool {
+		found = append(found, fmt.Sprintf("%s@%d", text[m.Start:m.End], m.Start))
+		return true
+	})
+	return found
+}
+
+func TestAhoCorasickFindAll(t *testing.T) {
+	tr := tripod.CreatePrefixStoreByteTrie(16)
+	for _, key := range []string{"he", "she", "his", "hers"} {
+		tr.Put([]byte(key))
+	}
+	a := tripod.CompileAhoCorasick(tr)
+
+	// The classic example: "she" and "he" end at the same offset, "hers"
+	// overlaps them.
+	expected := "[she@1 he@2 hers@2]"
+	if found := fmt.Sprint(findAll(a, "ushers")); found != expected {
+		t.Errorf("expected matches %s, got %s", expected, found)
+	}
+
+	if found := findAll(a, "xyz"); len(found) != 0 {
+		t.Errorf("expected no matches, got %v", found)
+	}
+
+	expected = "[his@0 she@3 he@4]"
+	if found := fmt.Sprint(findAll(a, "hisshe")); found != expected {
+		t.Errorf("expected matches %s, got %s", expected, found)
+	}
+
+	count := 0
+	a.FindAll([]byte("ushers"), func(m tripod.Match) bool {
+		count++
+		return false
+	})
+	if count != 1 {
+		t.Errorf("FindAll should stop when asked to, got %d matches", count)
+	}
+}
+
+func TestAhoCorasickMatchesNaiveSearch(t *testing.T) {
+	tr := tripod.CreatePrefixStoreByteTrie(4)
+	var keys [][]byte
+	for i := 0; i < 200; i++ {
+		key := getRandomByteSlice(1 + i%4)
+		if newlyAdded, _ := tr.Put(key); newlyAdded {
+			keys = append(keys, key)
+		}
+	}
+	a := tripod.CompileAhoCorasick(tr)
+	text := getRandomByteSlice(5000)
+
+	expected := 0
+	for _, key := range keys {
+		for i := 0; i+len(key) <= len(text); i++ {
+			if bytes.Equal(text[i:i+len(key)], key) {
+				expected++
+			}
+		}
+	}
+
+	found := 0
+	a.FindAll(text, func(m tripod.Match) bool {
+		if !tr.Exists(text[m.Start:m.End]) {
+			t.Errorf("match %q at %d is not a stored key", text[m.Start:m.End], m.Start)
+		}
+		found++
+		return true
+	})
+	if found != expected {
+		t.Errorf("expected %d matches, got %d", expected, found)
+	}
+}
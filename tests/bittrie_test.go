@@ -0,0 +1,141 @@
+package test_tripod
+
+import (
+	"fmt"
+	"github.com/arpitbbhayani/tripod"
+	"math/rand"
+	"net/netip"
+	"testing"
+)
+
+func createBitTrie(prefixes ...string) *tripod.PrefixStoreBitTrie {
+	tr := tripod.CreatePrefixStoreBitTrie()
+	for _, prefix := range prefixes {
+		tr.Put(netip.MustParsePrefix(prefix))
+	}
+	return tr
+}
+
+func TestPrefixStoreBitTriePut(t *testing.T) {
+	tr := tripod.CreatePrefixStoreBitTrie()
+
+	if newlyAdded, _ := tr.Put(netip.MustParsePrefix("10.16.0.0/20")); newlyAdded == false {
+		t.Errorf("adding prefix to trie: expected %t", true)
+	}
+
+	// Host bits are ignored, hence this is the same prefix.
+	if newlyAdded, _ := tr.Put(netip.MustParsePrefix("10.16.15.1/20")); newlyAdded == true {
+		t.Errorf("adding existing prefix to trie: expected %t", false)
+	}
+
+	if _, err := tr.Put(netip.Prefix{}); err == nil {
+		t.Errorf("adding invalid prefix should return an error")
+	}
+
+	if newlyAdded, _ := tr.Put(netip.MustParsePrefix("::/0")); newlyAdded == false {
+		t.Errorf("adding default route to trie: expected %t", true)
+	}
+
+	if tr.Exists(netip.MustParsePrefix("10.16.0.0/20")) != true {
+		t.Errorf("expected prefix to exist")
+	}
+	if tr.Exists(netip.MustParsePrefix("10.16.0.0/21")) == true || tr.Exists(netip.MustParsePrefix("0.0.0.0/0")) == true {
+		t.Errorf("expected prefix not to exist")
+	}
+	if tr.Count() != 2 {
+		t.Errorf("expected %d prefixes, got %d", 2, tr.Count())
+	}
+}
+
+func TestPrefixStoreBitTrieLongestMatch(t *testing.T) {
+	tr := createBitTrie("0.0.0.0/0", "10.0.0.0/8", "10.16.0.0/20", "10.16.8.0/21", "2001:db8::/32", "2001:db8:1::/48")
+
+	testCases := []struct {
+		addr     string
+		expected string
+	}{
+		{"10.16.9.1", "10.16.8.0/21"},
+		{"10.16.7.255", "10.16.0.0/20"},
+		{"10.17.0.1", "10.0.0.0/8"},
+		{"192.168.1.1", "0.0.0.0/0"},
+		{"2001:db8:1::1", "2001:db8:1::/48"},
+		{"2001:db8:2::1", "2001:db8::/32"},
+	}
+	for _, tc := range testCases {
+		match, ok := tr.LongestMatch(netip.MustParseAddr(tc.addr))
+		if !ok || match.String() != tc.expected {
+			t.Errorf("longest match for %s: expected %s, got %s", tc.addr, tc.expected, match)
+		}
+	}
+
+	// There is no IPv6 default route, and the families are kept apart.
+	if match, ok := tr.LongestMatch(netip.MustParseAddr("2002::1")); ok {
+		t.Errorf("expected no match, got %s", match)
+	}
+	if match, ok := tr.LongestMatch(netip.MustParseAddr("::ffff:10.16.9.1")); ok {
+		t.Errorf("expected no match for IPv4-mapped address, got %s", match)
+	}
+
+	if tr.Delete(netip.MustParsePrefix("10.16.8.0/21")) != true {
+		t.Errorf("deleting existing prefix should return %t", true)
+	}
+	if tr.Delete(netip.MustParsePrefix("10.16.8.0/22")) == true {
+		t.Errorf("deleting missing prefix should return %t", false)
+	}
+	if match, _ := tr.LongestMatch(netip.MustParseAddr("10.16.9.1")); match.String() != "10.16.0.0/20" {
+		t.Errorf("expected the covering prefix after deletion, got %s", match)
+	}
+}
+
+func TestPrefixStoreBitTrieCoveringAndCovered(t *testing.T) {
+	tr := createBitTrie("10.0.0.0/8", "10.16.0.0/20", "10.16.8.0/21", "10.16.0.0/24", "10.128.0.0/9", "172.16.0.0/12")
+
+	expected := "[10.0.0.0/8 10.16.0.0/20 10.16.0.0/24]"
+	if covering := fmt.Sprint(tr.Covering(netip.MustParsePrefix("10.16.0.0/24"))); covering != expected {
+		t.Errorf("expected covering prefixes %s, got %s", expected, covering)
+	}
+
+	expected = "[10.16.0.0/20 10.16.0.0/24 10.16.8.0/21]"
+	if covered := fmt.Sprint(tr.Covered(netip.MustParsePrefix("10.16.0.0/16"))); covered != expected {
+		t.Errorf("expected covered prefixes %s, got %s", expected, covered)
+	}
+
+	expected = "[10.0.0.0/8 10.16.0.0/20 10.16.0.0/24 10.16.8.0/21 10.128.0.0/9]"
+	if covered := fmt.Sprint(tr.Covered(netip.MustParsePrefix("10.0.0.0/8"))); covered != expected {
+		t.Errorf("expected covered prefixes %s, got %s", expected, covered)
+	}
+
+	if covered := tr.Covered(netip.MustParsePrefix("192.168.0.0/16")); len(covered) != 0 {
+		t.Errorf("expected no covered prefixes, got %v", covered)
+	}
+}
+
+func TestPrefixStoreBitTrieCountAfterChurn(t *testing.T) {
+	var prefixes []netip.Prefix
+	for bits := 0; bits <= 24; bits += 4 {
+		prefixes = append(prefixes,
+			netip.MustParsePrefix(fmt.Sprintf("10.1.2.0/%d", bits)).Masked(),
+			netip.MustParsePrefix(fmt.Sprintf("2001:db8::/%d", bits)).Masked())
+	}
+
+	r := rand.New(rand.NewSource(1))
+	tr := tripod.CreatePrefixStoreBitTrie()
+	present := make(map[netip.Prefix]bool)
+	for i := 0; i < 2000; i++ {
+		prefix := prefixes[r.Intn(len(prefixes))]
+		if r.Intn(3) == 0 {
+			if tr.Delete(prefix) != present[prefix] {
+				t.Fatalf("deleting %s: expected %t", prefix, present[prefix])
+			}
+			delete(present, prefix)
+		} else {
+			if added, _ := tr.Put(prefix); added == present[prefix] {
+				t.Fatalf("adding %s: expected %t", prefix, !present[prefix])
+			}
+			present[prefix] = true
+		}
+		if tr.Count() != len(present) {
+			t.Fatalf("expected %d prefixes, got %d", len(present), tr.Count())
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package test_tripod
+
+import (
+	"fmt"
+	"github.com/arpitbbhayani/tripod"
+	"math/rand"
+	"testing"
+)
+
+func TestBoundedPrefixStoreLRU(t *testing.T) {
+	s := tripod.CreateBoundedPrefixStore(16, tripod.BoundedOptions{MaxKeys: 3})
+	s.Put([]byte("go"))
+	s.Put([]byte("gopher"))
+	s.Put([]byte("rust"))
+
+	// "go" is used, hence "gopher" is the least recently used key.
+	s.Exists([]byte("go"))
+	if newlyAdded, _ := s.Put([]byte("zig")); newlyAdded == false {
+		t.Errorf("adding key to full store: expected %t", true)
+	}
+	if s.Exists([]byte("gopher")) || s.Count() != 3 || s.Evictions() != 1 {
+		t.Errorf("expected the least recently used key to be evicted")
+	}
+	if keys := prefixSearchSorted(s, "go"); len(keys) != 1 || keys[0] != "go" {
+		t.Errorf("unexpected prefix search results %v", keys)
+	}
+
+	s.Put([]byte("go"))
+	s.Put([]byte("java"))
+	if s.Exists([]byte("rust")) || !s.Exists([]byte("go")) {
+		t.Errorf("expected the least recently used key to be evicted")
+	}
+}
+
+func TestBoundedPrefixStoreLFU(t *testing.T) {
+	s := tripod.CreateBoundedPrefixStore(16, tripod.BoundedOptions{MaxKeys: 2, Policy: tripod.EvictLFU})
+	s.Put([]byte("go"))
+	s.Put([]byte("rust"))
+	s.Exists([]byte("go"))
+	s.Exists([]byte("rust"))
+	s.Exists([]byte("rust"))
+
+	// The key just put is the least frequently used one, yet it is kept.
+	s.Put([]byte("zig"))
+	if !s.Exists([]byte("zig")) || s.Exists([]byte("go")) || !s.Exists([]byte("rust")) {
+		t.Errorf("expected the least frequently used key to be evicted")
+	}
+}
+
+func TestBoundedPrefixStoreMaxBytes(t *testing.T) {
+	empty := tripod.CreateBoundedPrefixStore(64, tripod.BoundedOptions{}).EstimatedBytes()
+	s := tripod.CreateBoundedPrefixStore(64, tripod.BoundedOptions{MaxBytes: 100 * empty})
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		key := []byte(fmt.Sprintf("%x", r.Int63()))
+		if r.Intn(4) == 0 {
+			s.Delete(key)
+		} else {
+			s.Put(key)
+		}
+		if s.EstimatedBytes() > 100*empty && s.Count() > 1 {
+			t.Fatalf("expected at most %d bytes, got %d", 100*empty, s.EstimatedBytes())
+		}
+	}
+	if s.Evictions() == 0 || s.Count() < 2 {
+		t.Errorf("expected keys to be evicted, and several to be left")
+	}
+
+	// The estimate follows the nodes pruned on eviction: it matches the one
+	// of a store built with the keys left.
+	rebuilt := tripod.CreateBoundedPrefixStore(64, tripod.BoundedOptions{})
+	for _, key := range prefixSearchSorted(s, "") {
+		rebuilt.Put([]byte(key))
+	}
+	if rebuilt.EstimatedBytes() != s.EstimatedBytes() {
+		t.Errorf("expected %d estimated bytes, got %d", rebuilt.EstimatedBytes(), s.EstimatedBytes())
+	}
+}
+
+func TestBoundedPrefixStoreEstimatedBytes(t *testing.T) {
+	s := tripod.CreateBoundedPrefixStore(16, tripod.BoundedOptions{})
+	empty := s.EstimatedBytes()
+	for _, key := range []string{"team", "tea", "ten", "to", "tea"} {
+		s.Put([]byte(key))
+	}
+	for _, key := range []string{"team", "ten", "to", "tea", "java"} {
+		s.Delete([]byte(key))
+	}
+	if s.EstimatedBytes() != empty || s.Count() != 0 {
+		t.Errorf("expected %d estimated bytes once empty, got %d", empty, s.EstimatedBytes())
+	}
+}
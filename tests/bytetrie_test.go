@@ -70,7 +70,7 @@ func TestPrefixStoreByteTriePut(t *testing.T) {
 
 	for key, _ := range hugeDataset {
 		if tr.Exists([]byte(key)) != true {
-			t.Errorf("key %s should be there in the PrefixStore %s", key)
+			t.Errorf("key %s should be there in the PrefixStore", key)
 		}
 	}
 
@@ -146,7 +146,7 @@ func TestPrefixStoreByteTriePrefixSearch(t *testing.T) {
 	for e := results.Front(); e != nil; e = e.Next() {
 		val := string(e.Value.([]byte))
 		if dataset[val] == false {
-			t.Errorf("improper value %d retrieved from trie during full trie PrefixSearch", val)
+			t.Errorf("improper value %s retrieved from trie during full trie PrefixSearch", val)
 		}
 	}
 
@@ -157,7 +157,7 @@ func TestPrefixStoreByteTriePrefixSearch(t *testing.T) {
 	for e := results.Front(); e != nil; e = e.Next() {
 		val := string(e.Value.([]byte))
 		if dataset[val] == false {
-			t.Errorf("improper value %d retrieved from trie during full trie PrefixSearch", val)
+			t.Errorf("improper value %s retrieved from trie during full trie PrefixSearch", val)
 		}
 	}
 
@@ -168,7 +168,7 @@ func TestPrefixStoreByteTriePrefixSearch(t *testing.T) {
 	for e := results.Front(); e != nil; e = e.Next() {
 		val := string(e.Value.([]byte))
 		if prefixDataset[val] == false {
-			t.Errorf("improper value %d retrieved from trie during full trie PrefixSearch", val)
+			t.Errorf("improper value %s retrieved from trie during full trie PrefixSearch", val)
 		}
 	}
 
@@ -179,7 +179,7 @@ func TestPrefixStoreByteTriePrefixSearch(t *testing.T) {
 	for e := results.Front(); e != nil; e = e.Next() {
 		val := string(e.Value.([]byte))
 		if prefixDataset[val] == false {
-			t.Errorf("improper value %d retrieved from trie during full trie PrefixSearch", val)
+			t.Errorf("improper value %s retrieved from trie during full trie PrefixSearch", val)
 		}
 	}
 
@@ -188,3 +188,41 @@ func TestPrefixStoreByteTriePrefixSearch(t *testing.T) {
 		t.Errorf("expected elements in trie are %d, but there are %d elements", 0, count)
 	}
 }
+
+func TestPrefixStoreByteTrieDelete(t *testing.T) {
+	tr := tripod.CreatePrefixStoreByteTrie(128)
+	populatePrefixStoreByteTrie(tr)
+
+	if deleted := tr.Delete([]byte("tes")); deleted == true {
+		t.Errorf("deleting non-existent key but for which path exists should return %t", false)
+	}
+
+	if deleted := tr.Delete([]byte("test")); deleted == false {
+		t.Errorf("deleting existing key should return %t", true)
+	}
+
+	if isPresent := tr.Exists([]byte("test")); isPresent == true {
+		t.Errorf("fetching deleted key from trie should return %t", false)
+	}
+
+	if isPresent := tr.Exists([]byte("test123")); isPresent == false {
+		t.Errorf("deleting a key should not delete the keys it is prefix of")
+	}
+
+	if deleted := tr.Delete([]byte("test")); deleted == true {
+		t.Errorf("deleting already deleted key should return %t", false)
+	}
+
+	if deleted := tr.Delete([]byte("test123")); deleted == false {
+		t.Errorf("deleting existing key should return %t", true)
+	}
+
+	// Only "te" remains, hence the nodes of "test123" beyond it are pruned.
+	if stats := tr.Stats(); stats.NodeCount != 3 {
+		t.Errorf("expected deleted paths to be pruned to %d nodes, got %d", 3, stats.NodeCount)
+	}
+
+	if count := tr.Count(); count != 1 {
+		t.Errorf("expected %d keys after deletion, got %d", 1, count)
+	}
+}
@@ -0,0 +1,90 @@
+package test_tripod
+
+import (
+	"github.com/arpitbbhayani/tripod"
+	"strings"
+	"testing"
+)
+
+const (
+	// "e" followed by U+0301 COMBINING ACUTE ACCENT.
+	decomposedE = "e\u0301"
+
+	// Woman, ZWJ, laptop: a single user-perceived character.
+	womanTechnologist = "\U0001F469\u200d\U0001F4BB"
+)
+
+func TestPrefixStoreGraphemeTriePut(t *testing.T) {
+	tr := tripod.CreatePrefixStoreGraphemeTrie(4)
+
+	// 5 runes but 4 grapheme clusters, hence within maxSize.
+	if newlyAdded, _ := tr.Put("caf" + decomposedE); newlyAdded == false {
+		t.Errorf("adding key to trie: expected %t", true)
+	}
+
+	if _, err := tr.Put("cafés"); err == nil {
+		t.Errorf("adding data more than maxSize specified should return an error")
+	}
+
+	if newlyAdded, _ := tr.Put(""); newlyAdded == true {
+		t.Errorf("adding empty string to trie, expected %t", false)
+	}
+
+	if _, err := tr.Put("\xff"); err == nil {
+		t.Errorf("adding invalid UTF-8 should return an error")
+	}
+}
+
+func TestPrefixStoreGraphemeTriePrefixSearch(t *testing.T) {
+	tr := tripod.CreatePrefixStoreGraphemeTrie(16)
+	keys := []string{
+		"caf" + decomposedE,
+		"cafe",
+		"\U0001F469",
+		womanTechnologist,
+		womanTechnologist + " at work",
+	}
+	for _, key := range keys {
+		tr.Put(key)
+	}
+
+	// "cafe" matches, but not "café" whose last character merely starts
+	// with an "e".
+	if results := tr.PrefixSearch("cafe"); len(results) != 1 || results[0] != "cafe" {
+		t.Errorf("prefix ending mid grapheme cluster should not match, got %q", results)
+	}
+
+	if results := tr.PrefixSearch("caf"); len(results) != 2 {
+		t.Errorf("expected number of elements in trie for given prefix are %d, but there are %d elements", 2, len(results))
+	}
+
+	// The woman emoji alone is a key, and a prefix of neither ZWJ sequence.
+	if results := tr.PrefixSearch("\U0001F469"); len(results) != 1 {
+		t.Errorf("prefix ending mid emoji ZWJ sequence should not match the sequence, got %q", results)
+	}
+	if results := tr.PrefixSearch("\U0001F469\u200d"); len(results) != 0 {
+		t.Errorf("prefix ending with a dangling ZWJ should match nothing, got %q", results)
+	}
+	if results := strings.Join(tr.PrefixSearch(womanTechnologist), ","); results != womanTechnologist+","+womanTechnologist+" at work" {
+		t.Errorf("expected keys starting with the whole sequence, got %q", results)
+	}
+
+	if tr.Exists("caf"+decomposedE) != true || tr.Exists("caf") == true {
+		t.Errorf("Exists should match whole keys only")
+	}
+
+	if tr.Delete(womanTechnologist) != true || tr.Count() != 4 {
+		t.Errorf("deleting existing key should return %t", true)
+	}
+	if tr.Exists(womanTechnologist+" at work") != true {
+		t.Errorf("deleting a key should not delete the keys it is prefix of")
+	}
+}
+
+func TestPrefixStoreGraphemeTrieCountAfterChurn(t *testing.T) {
+	tr := tripod.CreatePrefixStoreGraphemeTrie(16)
+	exerciseCount(t, "PrefixStoreGraphemeTrie",
+		func(key string) bool { added, _ := tr.Put(key); return added },
+		tr.Delete, tr.Count,
+		func() int { return len(tr.PrefixSearch("")) })
+}
@@ -0,0 +1,117 @@
+package test_tripod
+
+import (
+	"encoding/json"
+	"github.com/arpitbbhayani/tripod"
+	"github.com/arpitbbhayani/tripod/tripodprom"
+	"github.com/prometheus/client_golang/prometheus"
+	"testing"
+)
+
+func TestInstrumentedBytePrefixStore(t *testing.T) {
+	tr := tripod.CreatePrefixStoreByteTrie(16)
+	tr.Put([]byte("preloaded"))
+
+	s := tripod.CreateInstrumentedBytePrefixStore(tr)
+	s.Put([]byte("go"))
+	s.Put([]byte("gopher"))
+	s.Put([]byte("go"))
+	s.Exists([]byte("go"))
+	s.PrefixSearch([]byte("go"))
+	s.PrefixSearch([]byte("x"))
+	s.Delete([]byte("gopher"))
+	s.Delete([]byte("gopher"))
+
+	snapshot := s.Metrics().Snapshot()
+	if snapshot.Keys != 2 || int(snapshot.Keys) != tr.Count() {
+		t.Errorf("expected %d keys, got %d", 2, snapshot.Keys)
+	}
+	if snapshot.Put.Count != 3 || snapshot.Exists.Count != 1 || snapshot.PrefixSearch.Count != 2 || snapshot.Delete.Count != 2 {
+		t.Errorf("unexpected number of calls: %d, %d, %d and %d",
+			snapshot.Put.Count, snapshot.Exists.Count, snapshot.PrefixSearch.Count, snapshot.Delete.Count)
+	}
+
+	// One search returned nothing, the other "go" and "gopher": buckets 0
+	// and 2.
+	results := snapshot.PrefixSearchResults
+	if results.Sum != 2 || results.Counts[0] != 1 || results.Counts[2] != 1 {
+		t.Errorf("unexpected result size histogram %v with sum %d", results.Counts, results.Sum)
+	}
+
+	var published map[string]interface{}
+	if err := json.Unmarshal([]byte(s.Metrics().String()), &published); err != nil {
+		t.Errorf("expvar representation should be JSON: %s", err)
+	}
+	if published["Keys"] != float64(2) {
+		t.Errorf("expected %d keys in expvar representation, got %v", 2, published["Keys"])
+	}
+}
+
+func TestInstrumentedPrefixStoreAllocations(t *testing.T) {
+	s := tripod.CreateInstrumentedRunePrefixStore(tripod.CreatePrefixStoreRuneTrie(16))
+	key := []rune("gopher")
+	s.Put(key)
+
+	if allocs := testing.AllocsPerRun(100, func() { s.Put(key) }); allocs != 0 {
+		t.Errorf("instrumented Put should not allocate, got %v", allocs)
+	}
+	if allocs := testing.AllocsPerRun(100, func() { s.Exists(key) }); allocs != 0 {
+		t.Errorf("instrumented Exists should not allocate, got %v", allocs)
+	}
+}
+
+func TestPrometheusCollector(t *testing.T) {
+	s := tripod.CreateInstrumentedBytePrefixStore(tripod.CreatePrefixStoreByteTrie(16))
+	s.Put([]byte("go"))
+	s.PrefixSearch([]byte("g"))
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(tripodprom.CreateCollector(map[string]*tripod.StoreMetrics{"words": s.Metrics()}))
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("gathering metrics: %s", err)
+	}
+
+	found := make(map[string]int)
+	for _, family := range families {
+		found[family.GetName()] = len(family.GetMetric())
+	}
+	expected := map[string]int{
+		"tripod_store_keys":                       1,
+		"tripod_store_operation_duration_seconds": 4,
+		"tripod_store_prefix_search_results":      1,
+	}
+	for name, count := range expected {
+		if found[name] != count {
+			t.Errorf("expected %d metrics %s, got %d", count, name, found[name])
+		}
+	}
+}
+
+func TestInstrumentedKeysAfterEviction(t *testing.T) {
+	bounded := tripod.CreateBoundedPrefixStore(16, tripod.BoundedOptions{MaxKeys: 2})
+	s := tripod.CreateInstrumentedBytePrefixStore(bounded)
+	for _, key := range []string{"go", "gopher", "golang", "gofmt"} {
+		s.Put([]byte(key))
+	}
+
+	if snapshot := s.Metrics().Snapshot(); snapshot.Keys != 2 {
+		t.Errorf("expected %d keys after eviction, got %d", 2, snapshot.Keys)
+	}
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(tripodprom.CreateCollector(map[string]*tripod.StoreMetrics{"words": s.Metrics()}))
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("gathering metrics: %s", err)
+	}
+	for _, family := range families {
+		if family.GetName() != "tripod_store_keys" {
+			continue
+		}
+		if keys := family.GetMetric()[0].GetGauge().GetValue(); keys != 2 {
+			t.Errorf("expected gauge of %d keys after eviction, got %v", 2, keys)
+		}
+	}
+}
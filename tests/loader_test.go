@@ -0,0 +1,107 @@
+package test_tripod
+
+import (
+	"github.com/arpitbbhayani/tripod"
+	"strings"
+	"testing"
+)
+
+func TestPrefixStoreByteTrieLoadFromText(t *testing.T) {
+	tr := tripod.CreatePrefixStoreByteTrie(8)
+	input := "go\nis\n\ngood\ngo\nwaytoolongkey\r\ngopher"
+
+	stats, err := tr.LoadFrom(strings.NewReader(input), tripod.LoadOptions{})
+	if err != nil {
+		t.Errorf("loading from a valid stream should not return an error, got %v", err)
+	}
+
+	if stats.Lines != 7 {
+		t.Errorf("expected %d lines to be read, but %d were read", 7, stats.Lines)
+	}
+	if stats.Added != 4 {
+		t.Errorf("expected %d keys to be added, but %d were added", 4, stats.Added)
+	}
+	if stats.Duplicates != 1 {
+		t.Errorf("expected %d duplicate, but got %d", 1, stats.Duplicates)
+	}
+	if stats.Skipped != 1 {
+		t.Errorf("expected %d blank line to be skipped, but got %d", 1, stats.Skipped)
+	}
+	if stats.Failed != 1 || len(stats.Errors) != 1 || stats.Errors[0].Line != 6 {
+		t.Errorf("expected oversize key on line %d to be reported, got %v", 6, stats.Errors)
+	}
+
+	for _, key := range []string{"go", "is", "good", "gopher"} {
+		if tr.Exists([]byte(key)) != true {
+			t.Errorf("key %s should be there in the PrefixStore", key)
+		}
+	}
+}
+
+func TestPrefixStoreByteTrieLoadFromTSV(t *testing.T) {
+	tr := tripod.CreatePrefixStoreByteTrie(16)
+	input := "apple\t1.5\tfruit\nbanana\tnotanumber\ncarrot\t\tveggie\ndate\n"
+
+	weights := make(map[string]float64)
+	values := make(map[string]string)
+	stats, _ := tr.LoadFrom(strings.NewReader(input), tripod.LoadOptions{
+		Format: tripod.LoadFormatTSV,
+		OnRecord: func(record tripod.LoadRecord) {
+			if record.HasWeight {
+				weights[record.Key] = record.Weight
+			}
+			values[record.Key] = record.Value
+		},
+	})
+
+	if stats.Added != 3 || stats.Failed != 1 {
+		t.Errorf("expected %d added and %d failed, got %d and %d", 3, 1, stats.Added, stats.Failed)
+	}
+	if weights["apple"] != 1.5 || values["apple"] != "fruit" {
+		t.Errorf("weight and value for apple not reported, got %v and %q", weights["apple"], values["apple"])
+	}
+	if _, ok := weights["carrot"]; ok || values["carrot"] != "veggie" {
+		t.Errorf("carrot should have a value and no weight")
+	}
+	if tr.Exists([]byte("banana")) == true {
+		t.Errorf("key from a line with invalid weight should not be added")
+	}
+}
+
+func TestPrefixStoreRuneTrieLoadFromNDJSON(t *testing.T) {
+	tr := tripod.CreatePrefixStoreRuneTrie(4)
+	input := strings.Join([]string{
+		`{"k": "café", "w": 3, "value": {"id": 1}}`,
+		`{"k": "cafés"}`,
+		`{"k": "thé", "w": "high"}`,
+		`{"key": "missing"}`,
+		`not json`,
+	}, "\n")
+
+	var records []tripod.LoadRecord
+	stats, err := tr.LoadFrom(strings.NewReader(input), tripod.LoadOptions{
+		Format:      tripod.LoadFormatNDJSON,
+		KeyField:    "k",
+		WeightField: "w",
+		MaxErrors:   2,
+		OnRecord: func(record tripod.LoadRecord) {
+			records = append(records, record)
+		},
+	})
+
+	if err != nil {
+		t.Errorf("loading from a valid stream should not return an error, got %v", err)
+	}
+	if stats.Added != 1 || stats.Failed != 4 {
+		t.Errorf("expected %d added and %d failed, got %d and %d", 1, 4, stats.Added, stats.Failed)
+	}
+	if len(stats.Errors) != 2 {
+		t.Errorf("expected only %d errors to be retained, got %d", 2, len(stats.Errors))
+	}
+	if len(records) != 1 || records[0].Weight != 3 || records[0].Value != `{"id": 1}` {
+		t.Errorf("unexpected records reported %v", records)
+	}
+	if tr.Exists([]rune("café")) != true {
+		t.Errorf("key %s should be there in the PrefixStore", "café")
+	}
+}
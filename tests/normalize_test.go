@@ -0,0 +1,70 @@
+package test_tripod
+
+import (
+	"github.com/arpitbbhayani/tripod"
+	"testing"
+)
+
+func TestNormalizers(t *testing.T) {
+	cases := []struct {
+		normalizer tripod.Normalizer
+		key        string
+		expected   string
+	}{
+		{tripod.FoldCase, "CAFÉ", "café"},
+		{tripod.FoldCase, "Straße", "strasse"},
+		{tripod.NFC, "café", "café"},
+		{tripod.NFKC, "ﬁne①", "fine1"},
+		{tripod.StripAccents, "Crème Brûlée", "Creme Brulee"},
+		{tripod.ChainNormalizers(tripod.StripAccents, tripod.FoldCase), "CAFÉ", "cafe"},
+	}
+	for _, c := range cases {
+		if normalized := string(c.normalizer([]rune(c.key))); normalized != c.expected {
+			t.Errorf("expected %s to be normalized to %s, got %s", c.key, c.expected, normalized)
+		}
+	}
+}
+
+func TestNormalizedPrefixStoreRuneTrie(t *testing.T) {
+	tr := tripod.CreateNormalizedPrefixStoreRuneTrie(16,
+		tripod.ChainNormalizers(tripod.NFC, tripod.StripAccents, tripod.FoldCase))
+
+	if newlyAdded, _ := tr.Put([]rune("Café")); newlyAdded == false {
+		t.Errorf("adding key to trie: expected %t", true)
+	}
+	if newlyAdded, _ := tr.Put([]rune("CAFE")); newlyAdded == true {
+		t.Errorf("adding key normalizing to an existing key: expected %t", false)
+	}
+	if newlyAdded, _ := tr.Put([]rune("cafétéria")); newlyAdded == false {
+		t.Errorf("adding key to trie: expected %t", true)
+	}
+
+	for _, key := range []string{"cafe", "CAFÉ", "café", "Café"} {
+		if tr.Exists([]rune(key)) != true {
+			t.Errorf("key %s should match a key in the PrefixStore", key)
+		}
+	}
+
+	results := tr.PrefixSearch([]rune("CAF"))
+	if count := results.Len(); count != 2 {
+		t.Errorf("expected elements in trie are %d, but there are %d elements", 2, count)
+	}
+	for e := results.Front(); e != nil; e = e.Next() {
+		if val := string(e.Value.([]rune)); val != "Café" && val != "cafétéria" {
+			t.Errorf("PrefixSearch should return original keys, got %s", val)
+		}
+	}
+
+	if deleted := tr.Delete([]rune("CAFÉ")); deleted == false {
+		t.Errorf("deleting key normalizing to an existing key should return %t", true)
+	}
+	if tr.Exists([]rune("Café")) == true || tr.Count() != 1 {
+		t.Errorf("deleted key should not be present")
+	}
+
+	// "ﬃ" is one rune that normalizes to three.
+	ligatures := tripod.CreateNormalizedPrefixStoreRuneTrie(2, tripod.NFKC)
+	if _, err := ligatures.Put([]rune("ﬃ")); err == nil {
+		t.Errorf("adding key whose normalized form is more than maxSize should return an error")
+	}
+}
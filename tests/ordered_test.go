@@ -0,0 +1,154 @@
+package test_tripod
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/arpitbbhayani/tripod"
+	"math/rand"
+	"slices"
+	"sort"
+	"testing"
+)
+
+func TestPrefixStoreByteTrieOrderedNavigation(t *testing.T) {
+	tr := tripod.CreatePrefixStoreByteTrie(16)
+	if _, found := tr.Min(); found {
+		t.Errorf("empty trie should have no min")
+	}
+	if _, found := tr.Floor([]byte("a")); found {
+		t.Errorf("empty trie should have no floor")
+	}
+
+	for _, key := range []string{"b", "ba", "bat", "bc", "d"} {
+		tr.Put([]byte(key))
+	}
+
+	testCases := []struct {
+		name     string
+		fn       func([]byte) ([]byte, bool)
+		key      string
+		expected string
+	}{
+		{"Floor", tr.Floor, "bb", "bat"},
+		{"Floor", tr.Floor, "ba", "ba"},
+		{"Floor", tr.Floor, "a", ""},
+		{"Floor", tr.Floor, "zzz", "d"},
+		{"Ceiling", tr.Ceiling, "bb", "bc"},
+		{"Ceiling", tr.Ceiling, "ba", "ba"},
+		{"Ceiling", tr.Ceiling, "", "b"},
+		{"Ceiling", tr.Ceiling, "da", ""},
+		{"Successor", tr.Successor, "ba", "bat"},
+		{"Successor", tr.Successor, "bat", "bc"},
+		{"Successor", tr.Successor, "d", ""},
+		{"Predecessor", tr.Predecessor, "ba", "b"},
+		{"Predecessor", tr.Predecessor, "c", "bc"},
+		{"Predecessor", tr.Predecessor, "b", ""},
+	}
+	for _, tc := range testCases {
+		result, _ := tc.fn([]byte(tc.key))
+		if string(result) != tc.expected {
+			t.Errorf("%s(%q): expected %q, got %q", tc.name, tc.key, tc.expected, result)
+		}
+	}
+
+	if first, _ := tr.Min(); string(first) != "b" {
+		t.Errorf("expected min %q, got %q", "b", first)
+	}
+	if last, _ := tr.Max(); string(last) != "d" {
+		t.Errorf("expected max %q, got %q", "d", last)
+	}
+
+	var keys []string
+	tr.Range([]byte("ba"), []byte("c"), func(key []byte) bool {
+		keys = append(keys, string(key))
+		return true
+	})
+	if fmt.Sprint(keys) != "[ba bat bc]" {
+		t.Errorf("expected range %s, got %v", "[ba bat bc]", keys)
+	}
+
+	keys = nil
+	tr.Range(nil, nil, func(key []byte) bool {
+		keys = append(keys, string(key))
+		return len(keys) < 2
+	})
+	if fmt.Sprint(keys) != "[b ba]" {
+		t.Errorf("range should stop when asked to, got %v", keys)
+	}
+}
+
+func TestPrefixStoreByteTrieOrderedMatchesSortedKeys(t *testing.T) {
+	tr := tripod.CreatePrefixStoreByteTrie(4)
+	var keys [][]byte
+	for i := 0; i < 300; i++ {
+		key := []byte{byte('a' + rand.Intn(3)), byte('a' + rand.Intn(3)), byte('a' + rand.Intn(3))}[:1+rand.Intn(3)]
+		if newlyAdded, _ := tr.Put(key); newlyAdded {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return bytes.Compare(keys[i], keys[j]) < 0 })
+
+	for i := 0; i < 200; i++ {
+		probe := []byte{byte('a' + rand.Intn(4)), byte('a' + rand.Intn(4)), byte('a' + rand.Intn(4))}[:rand.Intn(4)]
+		at, found := sort.Find(len(keys), func(j int) int { return bytes.Compare(probe, keys[j]) })
+
+		ceiling, _ := tr.Ceiling(probe)
+		if at < len(keys) && !bytes.Equal(ceiling, keys[at]) || at == len(keys) && ceiling != nil {
+			t.Errorf("Ceiling(%q): got %q", probe, ceiling)
+		}
+
+		floor, _ := tr.Floor(probe)
+		expected := at - 1
+		if found {
+			expected = at
+		}
+		if expected >= 0 && !bytes.Equal(floor, keys[expected]) || expected < 0 && floor != nil {
+			t.Errorf("Floor(%q): got %q", probe, floor)
+		}
+	}
+
+	var ranged [][]byte
+	tr.Range(keys[1], keys[len(keys)-1], func(key []byte) bool {
+		ranged = append(ranged, slices.Clone(key))
+		return true
+	})
+	if len(ranged) != len(keys)-2 {
+		t.Errorf("expected %d keys in range, got %d", len(keys)-2, len(ranged))
+	}
+}
+
+func TestPrefixStoreRuneTrieOrderedNavigation(t *testing.T) {
+	tr := tripod.CreatePrefixStoreRuneTrie(16)
+	for _, key := range []string{"école", "eau", "été", "zèbre"} {
+		tr.Put([]rune(key))
+	}
+
+	// Runes are compared by code point, hence "é" sorts after "z".
+	if first, _ := tr.Min(); string(first) != "eau" {
+		t.Errorf("expected min %q, got %q", "eau", string(first))
+	}
+	if last, _ := tr.Max(); string(last) != "été" {
+		t.Errorf("expected max %q, got %q", "été", string(last))
+	}
+	if next, _ := tr.Successor([]rune("eau")); string(next) != "zèbre" {
+		t.Errorf("expected successor %q, got %q", "zèbre", string(next))
+	}
+	if prev, _ := tr.Predecessor([]rune("été")); string(prev) != "école" {
+		t.Errorf("expected predecessor %q, got %q", "école", string(prev))
+	}
+	if floor, _ := tr.Floor([]rune("f")); string(floor) != "eau" {
+		t.Errorf("expected floor %q, got %q", "eau", string(floor))
+	}
+	if ceiling, _ := tr.Ceiling([]rune("f")); string(ceiling) != "zèbre" {
+		t.Errorf("expected ceiling %q, got %q", "zèbre", string(ceiling))
+	}
+
+	var keys []string
+	tr.Range([]rune("f"), nil, func(key []rune) bool {
+		keys = append(keys, string(key))
+		return true
+	})
+	if fmt.Sprint(keys) != "[zèbre école été]" {
+		t.Errorf("expected range %s, got %v", "[zèbre école été]", keys)
+	}
+}
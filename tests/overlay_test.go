@@ -0,0 +1,103 @@
+package test_tripod
+
+import (
+	"github.com/arpitbbhayani/tripod"
+	"sort"
+	"testing"
+)
+
+func prefixSearchSorted(s tripod.BytePrefixStore, prefix string) []string {
+	var keys []string
+	entries := s.PrefixSearch([]byte(prefix))
+	for e := entries.Front(); e != nil; e = e.Next() {
+		keys = append(keys, string(e.Value.([]byte)))
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestOverlayPrefixStore(t *testing.T) {
+	base := createByteTrie("go", "gopher", "golang", "rust")
+	s := tripod.CreateOverlayPrefixStore(base, 16)
+
+	if s.Count() != 4 || !s.Exists([]byte("gopher")) {
+		t.Errorf("overlay should initially hold the keys of the base")
+	}
+
+	if newlyAdded, _ := s.Put([]byte("gofmt")); newlyAdded == false {
+		t.Errorf("adding key to overlay: expected %t", true)
+	}
+	if newlyAdded, _ := s.Put([]byte("golang")); newlyAdded == true {
+		t.Errorf("adding key of the base to overlay: expected %t", false)
+	}
+	if s.Delete([]byte("gopher")) != true || s.Exists([]byte("gopher")) {
+		t.Errorf("deleting key of the base should hide it")
+	}
+	if s.Delete([]byte("gopher")) == true {
+		t.Errorf("deleting hidden key should return %t", false)
+	}
+	if s.Delete([]byte("java")) == true {
+		t.Errorf("deleting missing key should return %t", false)
+	}
+
+	if keys := prefixSearchSorted(s, "go"); len(keys) != 3 || keys[0] != "go" || keys[1] != "gofmt" || keys[2] != "golang" {
+		t.Errorf("unexpected prefix search results %v", keys)
+	}
+	if keys := prefixSearchSorted(s, "ru"); len(keys) != 1 {
+		t.Errorf("unexpected prefix search results %v", keys)
+	}
+	if s.Count() != 4 {
+		t.Errorf("expected %d keys, got %d", 4, s.Count())
+	}
+
+	// The base is never modified.
+	if base.Count() != 4 || !base.Exists([]byte("gopher")) || base.Exists([]byte("gofmt")) {
+		t.Errorf("overlay should leave the base untouched")
+	}
+
+	if newlyAdded, _ := s.Put([]byte("gopher")); newlyAdded == false || !s.Exists([]byte("gopher")) {
+		t.Errorf("putting a hidden key of the base should make it visible again")
+	}
+	additions, tombstones := s.Layer()
+	if additions.Count() != 1 || tombstones.Count() != 0 {
+		t.Errorf("expected %d addition and %d tombstone, got %d and %d", 1, 0, additions.Count(), tombstones.Count())
+	}
+}
+
+func TestOverlayPrefixStoreDeleteLongBaseKey(t *testing.T) {
+	base := createByteTrie("go", "gophers-world")
+	s := tripod.CreateOverlayPrefixStore(base, 4)
+
+	if s.Delete([]byte("gophers-world")) != true || s.Exists([]byte("gophers-world")) {
+		t.Errorf("deleting key of the base longer than the layer keys should hide it")
+	}
+	if keys := prefixSearchSorted(s, "go"); len(keys) != 1 || keys[0] != "go" {
+		t.Errorf("unexpected prefix search results %v", keys)
+	}
+	if s.Count() != 1 {
+		t.Errorf("expected %d keys, got %d", 1, s.Count())
+	}
+
+	if newlyAdded, _ := s.Put([]byte("gophers-world")); newlyAdded == false || !s.Exists([]byte("gophers-world")) {
+		t.Errorf("putting a hidden long key of the base should make it visible again")
+	}
+	if _, err := s.Put([]byte("golang")); err == nil {
+		t.Errorf("adding key longer than the layer keys: expected an error")
+	}
+}
+
+func TestOverlayPrefixStoresShareBase(t *testing.T) {
+	base := createByteTrie("apple", "apricot", "banana")
+	first := tripod.CreateOverlayPrefixStore(base, 16)
+	second := tripod.CreateOverlayPrefixStore(base, 16)
+
+	first.Delete([]byte("apple"))
+	second.Put([]byte("avocado"))
+
+	if keys := prefixSearchSorted(first, "a"); len(keys) != 1 || keys[0] != "apricot" {
+		t.Errorf("unexpected prefix search results %v on first overlay", keys)
+	}
+	if keys := prefixSearchSorted(second, "a"); len(keys) != 3 {
+		t.Errorf("unexpected prefix search results %v on second overlay", keys)
+	}
+}
@@ -0,0 +1,132 @@
+package test_tripod
+
+import (
+	"fmt"
+	"github.com/arpitbbhayani/tripod"
+	"testing"
+)
+
+func createPathStore(separator string, keys ...string) *tripod.PathStore {
+	s := tripod.CreatePathStore(separator, 8)
+	for _, key := range keys {
+		s.Put(key)
+	}
+	return s
+}
+
+func TestPathStorePut(t *testing.T) {
+	s := tripod.CreatePathStore("/", 4)
+
+	if newlyAdded, _ := s.Put("/usr/local/bin"); newlyAdded == false {
+		t.Errorf("adding key to store: expected %t", true)
+	}
+	if newlyAdded, _ := s.Put("/usr/local/bin"); newlyAdded == true {
+		t.Errorf("adding existing key to store: expected %t", false)
+	}
+	if _, err := s.Put("/usr/local/bin/go"); err == nil {
+		t.Errorf("adding key with more segments than maxSize specified should return an error")
+	}
+	if newlyAdded, _ := s.Put(""); newlyAdded == true {
+		t.Errorf("adding empty key to store, expected %t", false)
+	}
+
+	if s.Exists("/usr/local/bin") != true {
+		t.Errorf("expected key to exist")
+	}
+	if s.Exists("/usr/local") == true || s.Exists("/usr/local/bin/") == true || s.Exists("usr/local/bin") == true {
+		t.Errorf("expected only the exact key to exist")
+	}
+}
+
+func TestPathStoreListing(t *testing.T) {
+	s := createPathStore("/", "/usr/local/bin", "/usr/local/lib", "/usr/lib", "/usr", "/etc/hosts", "/usr/localhost")
+
+	expected := "[lib local localhost]"
+	if children := fmt.Sprint(s.ListChildren("/usr")); children != expected {
+		t.Errorf("expected children %s, got %s", expected, children)
+	}
+	if children := fmt.Sprint(s.ListChildren("/usr/")); children != expected {
+		t.Errorf("a trailing separator should be ignored, expected %s, got %s", expected, children)
+	}
+	if children := fmt.Sprint(s.ListChildren("/")); children != "[etc usr]" {
+		t.Errorf("expected children of root %s, got %s", "[etc usr]", children)
+	}
+	if children := s.ListChildren("/usr/lo"); len(children) != 0 {
+		t.Errorf("partial segments should not match, got %v", children)
+	}
+
+	expected = "[/usr/local/bin /usr/local/lib]"
+	if subtree := fmt.Sprint(s.ListSubtree("/usr/local")); subtree != expected {
+		t.Errorf("expected subtree %s, got %s", expected, subtree)
+	}
+	if subtree := s.ListSubtree("/usr/lo"); len(subtree) != 0 {
+		t.Errorf("partial segments should not match, got %v", subtree)
+	}
+	if subtree := s.ListSubtree(""); len(subtree) != s.Count() || subtree[0] != "/etc/hosts" {
+		t.Errorf("expected all the keys, got %v", subtree)
+	}
+
+	if s.Delete("/usr/local/bin") != true || s.Delete("/usr/local") == true {
+		t.Errorf("deleting should only delete existing keys")
+	}
+	if children := fmt.Sprint(s.ListChildren("/usr/local")); children != "[lib]" {
+		t.Errorf("expected children %s after deletion, got %s", "[lib]", children)
+	}
+}
+
+func TestPathStoreDottedKeys(t *testing.T) {
+	s := createPathStore(".", "server.http.port", "server.http.host", "server.grpc.port")
+
+	if subtree := fmt.Sprint(s.ListSubtree("server.http")); subtree != "[server.http.host server.http.port]" {
+		t.Errorf("unexpected subtree %s", subtree)
+	}
+	if children := fmt.Sprint(s.ListChildren("")); children != "[server]" {
+		t.Errorf("unexpected children %s", children)
+	}
+}
+
+func TestPathStoreMatch(t *testing.T) {
+	s := createPathStore("/", "/users/42/posts", "/users/7/posts", "/users/7/posts/3", "/users/7/likes", "/teams/1/posts")
+
+	matches := s.Match("/users/:id/posts")
+	if len(matches) != 2 {
+		t.Fatalf("expected %d matches, got %d", 2, len(matches))
+	}
+	if matches[0].Key != "/users/42/posts" || matches[0].Params["id"] != "42" {
+		t.Errorf("unexpected match %v", matches[0])
+	}
+	if matches[1].Key != "/users/7/posts" || matches[1].Params["id"] != "7" {
+		t.Errorf("unexpected match %v", matches[1])
+	}
+
+	matches = s.Match("/:kind/:id/posts")
+	if len(matches) != 3 || matches[0].Params["kind"] != "teams" || matches[0].Params["id"] != "1" {
+		t.Errorf("unexpected matches %v", matches)
+	}
+
+	matches = s.Match("/users/7/likes")
+	if len(matches) != 1 || matches[0].Params != nil {
+		t.Errorf("expected a single match without params, got %v", matches)
+	}
+
+	if matches = s.Match("/users/:id"); len(matches) != 0 {
+		t.Errorf("expected no match, got %v", matches)
+	}
+}
+
+func TestPathStoreCountAfterChurn(t *testing.T) {
+	s := tripod.CreatePathStore("/", 8)
+	exerciseCount(t, "PathStore",
+		func(key string) bool { added, _ := s.Put(key); return added },
+		s.Delete, s.Count,
+		func() int { return len(s.ListSubtree("")) })
+}
+
+func TestPathStoreEmptySeparator(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("creating PathStore with empty separator should panic")
+		}
+	}()
+	tripod.CreatePathStore("", 8)
+}
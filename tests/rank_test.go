@@ -0,0 +1,121 @@
+package test_tripod
+
+import (
+	"bytes"
+	"github.com/arpitbbhayani/tripod"
+	"sort"
+	"testing"
+)
+
+func TestPrefixStoreByteTrieRankAndSelect(t *testing.T) {
+	tr := tripod.CreatePrefixStoreByteTrie(16)
+	keys := []string{"b", "ba", "bat", "bc", "d"}
+	for _, key := range keys {
+		tr.Put([]byte(key))
+	}
+
+	for i, key := range keys {
+		if rank := tr.Rank([]byte(key)); rank != i {
+			t.Errorf("Rank(%q): expected %d, got %d", key, i, rank)
+		}
+		if selected, found := tr.Select(i); !found || string(selected) != key {
+			t.Errorf("Select(%d): expected %q, got %q", i, key, selected)
+		}
+	}
+
+	testCases := []struct {
+		key      string
+		expected int
+	}{
+		{"", 0},
+		{"a", 0},
+		{"bb", 3},
+		{"bat0", 3},
+		{"c", 4},
+		{"z", 5},
+	}
+	for _, tc := range testCases {
+		if rank := tr.Rank([]byte(tc.key)); rank != tc.expected {
+			t.Errorf("Rank(%q): expected %d, got %d", tc.key, tc.expected, rank)
+		}
+	}
+
+	if _, found := tr.Select(len(keys)); found {
+		t.Errorf("Select past the last key should not find any")
+	}
+	if _, found := tr.Select(-1); found {
+		t.Errorf("Select of a negative index should not find any")
+	}
+
+	tr.Delete([]byte("ba"))
+	if selected, _ := tr.Select(1); string(selected) != "bat" {
+		t.Errorf("expected %q after deletion, got %q", "bat", selected)
+	}
+	if tr.Count() != 4 {
+		t.Errorf("expected %d keys after deletion, got %d", 4, tr.Count())
+	}
+}
+
+func TestPrefixStoreRankAfterBuildFromSorted(t *testing.T) {
+	keys := make([][]byte, 0, 500)
+	seen := make(map[string]bool)
+	for i := 0; len(keys) < cap(keys); i++ {
+		key := getRandomByteSlice(1 + i%6)
+		if !seen[string(key)] {
+			seen[string(key)] = true
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return bytes.Compare(keys[i], keys[j]) < 0 })
+
+	i := 0
+	tr, err := tripod.BuildPrefixStoreByteTrieFromSorted(8, func() ([]byte, bool) {
+		if i == len(keys) {
+			return nil, false
+		}
+		i++
+		return keys[i-1], true
+	})
+	if err != nil {
+		t.Fatalf("building trie: %s", err)
+	}
+
+	if tr.Count() != len(keys) {
+		t.Errorf("expected %d keys, got %d", len(keys), tr.Count())
+	}
+	for i, key := range keys {
+		if rank := tr.Rank(key); rank != i {
+			t.Errorf("Rank(%q): expected %d, got %d", key, i, rank)
+		}
+		if selected, _ := tr.Select(i); !bytes.Equal(selected, key) {
+			t.Errorf("Select(%d): expected %q, got %q", i, key, selected)
+		}
+	}
+
+	// Keys put afterwards are counted as well.
+	tr.Put([]byte{0})
+	if selected, _ := tr.Select(0); !bytes.Equal(selected, []byte{0}) || tr.Rank(keys[0]) != 1 {
+		t.Errorf("expected the new smallest key at index 0, got %q", selected)
+	}
+}
+
+func TestPrefixStoreRuneTrieRankAndSelect(t *testing.T) {
+	tr := tripod.CreatePrefixStoreRuneTrie(16)
+	keys := []string{"eau", "zèbre", "école", "été"}
+	for _, key := range keys {
+		tr.Put([]rune(key))
+	}
+	tr.Put([]rune("eau"))
+
+	for i, key := range keys {
+		if rank := tr.Rank([]rune(key)); rank != i {
+			t.Errorf("Rank(%q): expected %d, got %d", key, i, rank)
+		}
+		if selected, _ := tr.Select(i); string(selected) != key {
+			t.Errorf("Select(%d): expected %q, got %q", i, key, string(selected))
+		}
+	}
+	if tr.Count() != len(keys) {
+		t.Errorf("expected %d keys, got %d", len(keys), tr.Count())
+	}
+}
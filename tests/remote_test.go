@@ -0,0 +1,206 @@
+package test_tripod
+
+import (
+	"context"
+	"fmt"
+	"github.com/arpitbbhayani/tripod"
+	"github.com/arpitbbhayani/tripod/remote"
+	"github.com/arpitbbhayani/tripod/remote/tripodpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+	"net"
+	"testing"
+)
+
+// Serves the stores over an in-memory connection and returns a Client to
+// them, along with a function to tear everything down.
+func startRemote(t *testing.T, server *remote.Server) (*remote.Client, func()) {
+	listener := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer()
+	tripodpb.RegisterPrefixStoreServiceServer(grpcServer, server)
+	go grpcServer.Serve(listener)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("connecting to remote server: %v", err)
+	}
+
+	return remote.CreateClient(conn, 0), func() {
+		conn.Close()
+		grpcServer.Stop()
+	}
+}
+
+// Exercises a BytePrefixStore the same way, whether local or remote.
+func exerciseBytePrefixStore(t *testing.T, store tripod.BytePrefixStore) {
+	for key := range dataset {
+		store.Put([]byte(key))
+	}
+
+	if _, err := store.Put(make([]byte, 129)); err == nil {
+		t.Errorf("adding data more than maxSize specified should return an error")
+	}
+	if store.Exists([]byte("test")) != true {
+		t.Errorf("fetching existing key from store should return %t", true)
+	}
+	if store.Exists([]byte("tes")) == true {
+		t.Errorf("fetching non-existent key from store should return %t", false)
+	}
+
+	results := store.PrefixSearch([]byte("tes"))
+	if count := results.Len(); count != 2 {
+		t.Errorf("expected elements in store are %d, but there are %d elements", 2, count)
+	}
+	for e := results.Front(); e != nil; e = e.Next() {
+		if val := string(e.Value.([]byte)); prefixDataset[val] == false {
+			t.Errorf("improper value %s retrieved from store during PrefixSearch", val)
+		}
+	}
+
+	if store.Delete([]byte("test")) != true {
+		t.Errorf("deleting existing key should return %t", true)
+	}
+	if count := store.Count(); count != 2 {
+		t.Errorf("expected %d keys after deletion, got %d", 2, count)
+	}
+}
+
+func TestRemoteByteStore(t *testing.T) {
+	exerciseBytePrefixStore(t, tripod.CreatePrefixStoreByteTrie(128))
+
+	server := remote.CreateServer()
+	server.AddByteStore("words", tripod.CreatePrefixStoreByteTrie(128))
+	client, stop := startRemote(t, server)
+	defer stop()
+
+	store := client.ByteStore("words")
+	exerciseBytePrefixStore(t, store)
+	if err := store.Err(); err != nil {
+		t.Errorf("remote calls should not fail, got %v", err)
+	}
+}
+
+func TestRemoteRuneStore(t *testing.T) {
+	server := remote.CreateServer()
+	server.AddRuneStore("words", tripod.CreatePrefixStoreRuneTrie(4))
+	client, stop := startRemote(t, server)
+	defer stop()
+
+	var store tripod.RunePrefixStore = client.RuneStore("words")
+	if added, err := store.Put([]rune("café")); !added || err != nil {
+		t.Errorf("adding key to remote store: expected %t, got %t and %v", true, added, err)
+	}
+	if _, err := store.Put([]rune("cafés")); err == nil {
+		t.Errorf("adding data more than maxSize specified should return an error")
+	}
+	if store.Exists([]rune("café")) != true {
+		t.Errorf("fetching existing key from store should return %t", true)
+	}
+	results := store.PrefixSearch([]rune("caf"))
+	if results.Len() != 1 || string(results.Front().Value.([]rune)) != "café" {
+		t.Errorf("expected PrefixSearch to return %s", "café")
+	}
+}
+
+func TestRemoteUnknownStore(t *testing.T) {
+	client, stop := startRemote(t, remote.CreateServer())
+	defer stop()
+
+	store := client.ByteStore("missing")
+	if store.Exists([]byte("key")) == true {
+		t.Errorf("fetching from unknown store should return %t", false)
+	}
+	if err := store.Err(); err == nil {
+		t.Errorf("fetching from unknown store should retain an error")
+	}
+	if _, err := store.Put([]byte("key")); err == nil {
+		t.Errorf("adding to unknown store should return an error")
+	}
+}
+
+func TestRemotePrefixSearchBatches(t *testing.T) {
+	local := tripod.CreatePrefixStoreByteTrie(128)
+	for i := 0; i < 1000; i++ {
+		local.Put(getRandomByteSlice(12))
+	}
+	server := remote.CreateServer()
+	server.AddByteStore("words", local)
+	client, stop := startRemote(t, server)
+	defer stop()
+
+	if count, expected := client.ByteStore("words").PrefixSearch(nil).Len(), local.Count(); count != expected {
+		t.Errorf("expected elements streamed are %d, but there are %d elements", expected, count)
+	}
+}
+
+func TestRemoteCallErrors(t *testing.T) {
+	client, stop := startRemote(t, remote.CreateServer())
+	defer stop()
+
+	store := client.ByteStore("missing")
+	ctx := context.Background()
+	if _, err := store.ExistsContext(ctx, []byte("key")); err == nil {
+		t.Errorf("fetching from unknown store should return an error")
+	}
+	if _, err := store.CountContext(ctx); err == nil {
+		t.Errorf("counting unknown store should return an error")
+	}
+	if _, err := store.PrefixSearchContext(ctx, []byte("k"), 0); err == nil {
+		t.Errorf("searching unknown store should return an error")
+	}
+	if _, err := store.DeleteContext(ctx, []byte("key")); err == nil {
+		t.Errorf("deleting from unknown store should return an error")
+	}
+	// Errors returned per call are not retained.
+	if err := store.Err(); err != nil {
+		t.Errorf("calls returning their error should not retain it, got %v", err)
+	}
+
+	if store.Count() != 0 || store.Err() == nil {
+		t.Errorf("counting unknown store should retain an error")
+	}
+}
+
+func TestRemotePrefixSearchLimit(t *testing.T) {
+	local := tripod.CreatePrefixStoreByteTrie(128)
+	for i := 0; i < 1000; i++ {
+		local.Put([]byte(fmt.Sprintf("k%04d", i)))
+	}
+	local.Put([]byte("z"))
+	server := remote.CreateServer()
+	server.AddByteStore("words", local)
+	// A store that does not walk its keys in order is searched as a whole.
+	server.AddByteStore("overlay", tripod.CreateOverlayPrefixStore(local, 128))
+	client, stop := startRemote(t, server)
+	defer stop()
+
+	for _, name := range []string{"words", "overlay"} {
+		store := client.ByteStore(name)
+		for _, limit := range []int{1, 255, 256, 257, 600, 0} {
+			results, err := store.PrefixSearchContext(context.Background(), []byte("k"), limit)
+			if err != nil {
+				t.Fatalf("remote calls should not fail, got %v", err)
+			}
+			expected := limit
+			if limit == 0 {
+				expected = 1000
+			}
+			if results.Len() != expected {
+				t.Errorf("%s: expected %d elements with limit %d, got %d", name, expected, limit, results.Len())
+			}
+			i := 0
+			for e := results.Front(); e != nil; e = e.Next() {
+				if key := string(e.Value.([]byte)); key != fmt.Sprintf("k%04d", i) {
+					t.Errorf("%s: expected key %d to be %s, got %s", name, i, fmt.Sprintf("k%04d", i), key)
+					break
+				}
+				i++
+			}
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package test_tripod
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/arpitbbhayani/tripod"
+	"github.com/arpitbbhayani/tripod/resp"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Minimal RESP client, talking to the server as a Redis client would.
+type respClient struct {
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// Sends the command as an array of bulk strings and returns its reply:
+// a string for simple strings and bulk strings, an int64 for integers, an
+// error for errors and a []interface{} for arrays.
+func (c *respClient) do(t *testing.T, args ...string) interface{} {
+	fmt.Fprintf(c.conn, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(c.conn, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	reply, err := c.readReply()
+	if err != nil {
+		t.Fatalf("reading reply to %v: %v", args, err)
+	}
+	return reply
+}
+
+func (c *respClient) readReply() (interface{}, error) {
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return fmt.Errorf("%s", line[1:]), nil
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		size, _ := strconv.Atoi(line[1:])
+		bulk := make([]byte, size+2)
+		if _, err := io.ReadFull(c.r, bulk); err != nil {
+			return nil, err
+		}
+		return string(bulk[:size]), nil
+	case '*':
+		count, _ := strconv.Atoi(line[1:])
+		elements := make([]interface{}, count)
+		for i := range elements {
+			if elements[i], err = c.readReply(); err != nil {
+				return nil, err
+			}
+		}
+		return elements, nil
+	}
+	return nil, fmt.Errorf("unexpected reply %q", line)
+}
+
+// Serves a byte store "words" and a rune store "runes" and returns a client
+// connected to it, along with a function to tear everything down.
+func startRESP(t *testing.T) (*respClient, func()) {
+	server := resp.CreateServer()
+	words := tripod.CreatePrefixStoreByteTrie(128)
+	populatePrefixStoreByteTrie(words)
+	server.AddByteStore("words", words)
+	server.AddRuneStore("runes", tripod.CreatePrefixStoreRuneTrie(4))
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listening: %v", err)
+	}
+	served := make(chan error, 1)
+	go func() { served <- server.Serve(l) }()
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatalf("connecting: %v", err)
+	}
+
+	return &respClient{conn: conn, r: bufio.NewReader(conn)}, func() {
+		conn.Close()
+		server.Close()
+		if err := <-served; err != resp.ErrServerClosed {
+			t.Errorf("Serve should return ErrServerClosed after Close, got %v", err)
+		}
+	}
+}
+
+func TestRESPCommands(t *testing.T) {
+	client, stop := startRESP(t)
+	defer stop()
+
+	if reply := client.do(t, "PING"); reply != "PONG" {
+		t.Errorf("expected PONG, got %v", reply)
+	}
+	if reply := client.do(t, "TEXISTS", "words", "test"); reply != int64(1) {
+		t.Errorf("fetching existing key should reply %d, got %v", 1, reply)
+	}
+	if reply := client.do(t, "texists", "words", "tes"); reply != int64(0) {
+		t.Errorf("fetching non-existent key should reply %d, got %v", 0, reply)
+	}
+	if reply := client.do(t, "TPUT", "words", "tea"); reply != int64(1) {
+		t.Errorf("adding key should reply %d, got %v", 1, reply)
+	}
+	if reply := client.do(t, "TPUT", "words", "tea"); reply != int64(0) {
+		t.Errorf("readding same key should reply %d, got %v", 0, reply)
+	}
+	if reply := client.do(t, "TCOUNT", "words"); reply != int64(4) {
+		t.Errorf("expected %d keys, got %v", 4, reply)
+	}
+
+	reply := client.do(t, "TPREFIX", "words", "te")
+	if keys := fmt.Sprint(reply); keys != "[te tea test test123]" {
+		t.Errorf("expected sorted keys for prefix, got %v", keys)
+	}
+	reply = client.do(t, "TPREFIX", "words", "te", "LIMIT", "2")
+	if keys := fmt.Sprint(reply); keys != "[te tea]" {
+		t.Errorf("expected limited keys for prefix, got %v", keys)
+	}
+
+	if reply := client.do(t, "TDEL", "words", "test"); reply != int64(1) {
+		t.Errorf("deleting existing key should reply %d, got %v", 1, reply)
+	}
+	if reply := client.do(t, "TDEL", "words", "test"); reply != int64(0) {
+		t.Errorf("deleting deleted key should reply %d, got %v", 0, reply)
+	}
+
+	if reply := client.do(t, "TPUT", "runes", "café"); reply != int64(1) {
+		t.Errorf("adding key should reply %d, got %v", 1, reply)
+	}
+	if reply := client.do(t, "TPREFIX", "runes", "caf"); fmt.Sprint(reply) != "[café]" {
+		t.Errorf("expected [café], got %v", reply)
+	}
+}
+
+func TestRESPErrors(t *testing.T) {
+	client, stop := startRESP(t)
+	defer stop()
+
+	for _, args := range [][]string{
+		{"GET", "key"},
+		{"TPUT", "words"},
+		{"TEXISTS", "missing", "key"},
+		{"TPUT", "runes", "cafés"},
+		{"TPREFIX", "words", "te", "LIMIT"},
+		{"TPREFIX", "words", "te", "LIMIT", "x"},
+	} {
+		if _, ok := client.do(t, args...).(error); !ok {
+			t.Errorf("command %v should reply with an error", args)
+		}
+	}
+
+	// The connection should still be usable after error replies.
+	if reply := client.do(t, "PING", "hello"); reply != "hello" {
+		t.Errorf("expected echo of PING, got %v", reply)
+	}
+}
+
+func TestRESPInlineAndPipelinedCommands(t *testing.T) {
+	client, stop := startRESP(t)
+	defer stop()
+
+	fmt.Fprint(client.conn, "TEXISTS words te\r\nTCOUNT words\r\nQUIT\r\n")
+	for _, expected := range []interface{}{int64(1), int64(3), "OK"} {
+		reply, err := client.readReply()
+		if err != nil || reply != expected {
+			t.Errorf("expected %v, got %v (%v)", expected, reply, err)
+		}
+	}
+	if _, err := client.readReply(); err != io.EOF {
+		t.Errorf("connection should be closed after QUIT, got %v", err)
+	}
+}
+
+func TestRESPNegativeMultibulkLength(t *testing.T) {
+	client, stop := startRESP(t)
+	defer stop()
+
+	fmt.Fprint(client.conn, "*-1\r\n*-5\r\n*0\r\n")
+	if reply := client.do(t, "PING"); reply != "PONG" {
+		t.Errorf("expected PONG after arrays of negative length, got %v", reply)
+	}
+	if reply := client.do(t, "TCOUNT", "words"); reply != int64(3) {
+		t.Errorf("expected %d keys, got %v", 3, reply)
+	}
+}
+
+func TestRESPPrefixLimitOnLargeStore(t *testing.T) {
+	server := resp.CreateServer()
+	words := tripod.CreatePrefixStoreByteTrie(16)
+	for i := 0; i < 10000; i++ {
+		words.Put([]byte(fmt.Sprintf("k%05d", i)))
+	}
+	server.AddByteStore("words", words)
+	// A store that does not walk its keys in order is searched as a whole.
+	server.AddByteStore("overlay", tripod.CreateOverlayPrefixStore(words, 16))
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listening: %v", err)
+	}
+	go server.Serve(l)
+	defer server.Close()
+	conn, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatalf("connecting: %v", err)
+	}
+	defer conn.Close()
+	client := &respClient{conn: conn, r: bufio.NewReader(conn)}
+
+	for _, name := range []string{"words", "overlay"} {
+		reply := client.do(t, "TPREFIX", name, "k0", "LIMIT", "3")
+		if keys := fmt.Sprint(reply); keys != "[k00000 k00001 k00002]" {
+			t.Errorf("%s: expected first keys for prefix, got %v", name, keys)
+		}
+		reply = client.do(t, "TPREFIX", name, "k0999")
+		if keys, _ := reply.([]interface{}); len(keys) != 10 || keys[9] != "k09999" {
+			t.Errorf("%s: expected %d keys for prefix, got %v", name, 10, reply)
+		}
+	}
+}
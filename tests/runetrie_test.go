@@ -84,7 +84,7 @@ func TestPrefixStoreRuneTriePut(t *testing.T) {
 
 	for key, _ := range hugeDataset {
 		if tr.Exists([]rune(key)) != true {
-			t.Errorf("key %s should be there in the PrefixStore %s", key)
+			t.Errorf("key %s should be there in the PrefixStore", key)
 		}
 	}
 
@@ -160,7 +160,7 @@ func TestPrefixStoreRuneTriePrefixSearch(t *testing.T) {
 	for e := results.Front(); e != nil; e = e.Next() {
 		val := string(e.Value.([]rune))
 		if dataset[val] == false {
-			t.Errorf("improper value %d retrieved from trie during full trie PrefixSearch", val)
+			t.Errorf("improper value %s retrieved from trie during full trie PrefixSearch", val)
 		}
 	}
 
@@ -171,7 +171,7 @@ func TestPrefixStoreRuneTriePrefixSearch(t *testing.T) {
 	for e := results.Front(); e != nil; e = e.Next() {
 		val := string(e.Value.([]rune))
 		if dataset[val] == false {
-			t.Errorf("improper value %d retrieved from trie during full trie PrefixSearch", val)
+			t.Errorf("improper value %s retrieved from trie during full trie PrefixSearch", val)
 		}
 	}
 
@@ -182,7 +182,7 @@ func TestPrefixStoreRuneTriePrefixSearch(t *testing.T) {
 	for e := results.Front(); e != nil; e = e.Next() {
 		val := string(e.Value.([]rune))
 		if prefixDataset[val] == false {
-			t.Errorf("improper value %d retrieved from trie during full trie PrefixSearch", val)
+			t.Errorf("improper value %s retrieved from trie during full trie PrefixSearch", val)
 		}
 	}
 
@@ -193,7 +193,7 @@ func TestPrefixStoreRuneTriePrefixSearch(t *testing.T) {
 	for e := results.Front(); e != nil; e = e.Next() {
 		val := string(e.Value.([]rune))
 		if prefixDataset[val] == false {
-			t.Errorf("improper value %d retrieved from trie during full trie PrefixSearch", val)
+			t.Errorf("improper value %s retrieved from trie during full trie PrefixSearch", val)
 		}
 	}
 
@@ -202,3 +202,33 @@ func TestPrefixStoreRuneTriePrefixSearch(t *testing.T) {
 		t.Errorf("expected elements in trie are %d, but there are %d elements", 0, count)
 	}
 }
+
+func TestPrefixStoreRuneTrieDelete(t *testing.T) {
+	tr := tripod.CreatePrefixStoreRuneTrie(128)
+	tr.Put([]rune("café"))
+	tr.Put([]rune("cafés"))
+
+	if deleted := tr.Delete([]rune("caf")); deleted == true {
+		t.Errorf("deleting non-existent key but for which path exists should return %t", false)
+	}
+
+	if deleted := tr.Delete([]rune("cafés")); deleted == false {
+		t.Errorf("deleting existing key should return %t", true)
+	}
+
+	if isPresent := tr.Exists([]rune("café")); isPresent == false {
+		t.Errorf("deleting a key should not delete its prefixes")
+	}
+
+	if count := tr.PrefixSearch([]rune("caf")).Len(); count != 1 {
+		t.Errorf("expected number of elements in trie for given prefix are %d, but there are %d elements", 1, count)
+	}
+
+	if deleted := tr.Delete([]rune("café")); deleted == false {
+		t.Errorf("deleting existing key should return %t", true)
+	}
+
+	if stats := tr.Stats(); stats.NodeCount != 1 || tr.Count() != 0 {
+		t.Errorf("expected empty trie after deleting all keys, got %d nodes and %d keys", stats.NodeCount, tr.Count())
+	}
+}
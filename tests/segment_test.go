@@ -0,0 +1,97 @@
+package test_tripod
+
+import (
+	"github.com/arpitbbhayani/tripod"
+	"strings"
+	"testing"
+)
+
+func createSegmenterTrie(words ...string) *tripod.PrefixStoreRuneTrie {
+	tr := tripod.CreatePrefixStoreRuneTrie(16)
+	for _, word := range words {
+		tr.Put([]rune(word))
+	}
+	return tr
+}
+
+func joinTokens(text []rune, tokens []tripod.Token) string {
+	words := make([]string, len(tokens))
+	for i, token := range tokens {
+		words[i] = string(text[token.Start:token.End])
+		if !token.Known {
+			words[i] = "?" + words[i]
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+func TestSegmenterModes(t *testing.T) {
+	tr := createSegmenterTrie("the", "them", "theme", "men", "me", "end", "mend", "s")
+	text := []rune("themends")
+
+	testCases := []struct {
+		mode     tripod.SegmentMode
+		expected string
+	}{
+		{tripod.SegmentForward, "theme ?n ?d s"},
+		{tripod.SegmentBackward, "the mend s"},
+		{tripod.SegmentBest, "the mend s"},
+	}
+	for _, tc := range testCases {
+		s := tripod.CreateSegmenter(tr, tc.mode, nil)
+		if segmented := joinTokens(text, s.Segment(nil, text)); segmented != tc.expected {
+			t.Errorf("mode %d: expected %q, got %q", tc.mode, tc.expected, segmented)
+		}
+	}
+}
+
+func TestSegmenterBestWeighted(t *testing.T) {
+	tr := createSegmenterTrie("now", "here", "no", "where", "nowhere")
+	text := []rune("nowhere")
+
+	s := tripod.CreateSegmenter(tr, tripod.SegmentBest, nil)
+	if segmented := joinTokens(text, s.Segment(nil, text)); segmented != "nowhere" {
+		t.Errorf("expected the fewest tokens, got %q", segmented)
+	}
+
+	weights := map[string]float64{"now": 5, "here": 5, "no": 1, "where": 1, "nowhere": 2}
+	s = tripod.CreateSegmenter(tr, tripod.SegmentBest, func(word []rune) float64 {
+		return weights[string(word)]
+	})
+	if segmented := joinTokens(text, s.Segment(nil, text)); segmented != "now here" {
+		t.Errorf("expected the highest weighted tokens, got %q", segmented)
+	}
+
+	// Known words are preferred over unknown runes, whatever the weights.
+	text = []rune("xnow")
+	if segmented := joinTokens(text, s.Segment(nil, text)); segmented != "?x now" {
+		t.Errorf("expected an unknown token followed by a word, got %q", segmented)
+	}
+
+	if tokens := s.Segment(nil, nil); len(tokens) != 0 {
+		t.Errorf("segmenting empty text should return no tokens, got %d", len(tokens))
+	}
+}
+
+func TestSegmenterAppendsWithoutAllocations(t *testing.T) {
+	tr := createSegmenterTrie("東京", "東京都", "京都", "都", "に", "住む")
+	text := []rune("東京都に住む")
+
+	for _, mode := range []tripod.SegmentMode{tripod.SegmentForward, tripod.SegmentBackward, tripod.SegmentBest} {
+		s := tripod.CreateSegmenter(tr, mode, nil)
+		dst := make([]tripod.Token, 1, 16)
+		dst[0] = tripod.Token{Start: -1}
+
+		tokens := s.Segment(dst, text)
+		if tokens[0].Start != -1 || joinTokens(text, tokens[1:]) != "東京都 に 住む" {
+			t.Errorf("mode %d: expected tokens appended to dst, got %q", mode, joinTokens(text, tokens[1:]))
+		}
+
+		allocs := testing.AllocsPerRun(100, func() {
+			s.Segment(dst[:0], text)
+		})
+		if allocs != 0 {
+			t.Errorf("mode %d: expected no allocations, got %v", mode, allocs)
+		}
+	}
+}
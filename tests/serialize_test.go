@@ -0,0 +1,153 @@
+package test_tripod
+
+import (
+	"bytes"
+	"github.com/arpitbbhayani/tripod"
+	"strings"
+	"testing"
+)
+
+func TestPrefixStoreByteTrieSerialization(t *testing.T) {
+	tr := tripod.CreatePrefixStoreByteTrie(128)
+	populatePrefixStoreByteTrie(tr)
+	for i := 0; i < 1000; i++ {
+		tr.Put(getRandomByteSlice(1 + i%127))
+	}
+
+	var buf bytes.Buffer
+	n, err := tr.WriteTo(&buf)
+	if err != nil {
+		t.Fatalf("serializing should not return an error, got %v", err)
+	}
+	if n != int64(buf.Len()) {
+		t.Errorf("expected %d bytes to be reported as written, but %d were reported", buf.Len(), n)
+	}
+
+	loaded, err := tripod.ReadPrefixStoreByteTrie(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("reading serialized store should not return an error, got %v", err)
+	}
+
+	expected := tr.PrefixSearch([]byte(""))
+	if count := loaded.PrefixSearch([]byte("")).Len(); count != expected.Len() {
+		t.Errorf("expected elements in trie are %d, but there are %d elements", expected.Len(), count)
+	}
+	for e := expected.Front(); e != nil; e = e.Next() {
+		if loaded.Exists(e.Value.([]byte)) != true {
+			t.Errorf("key %s should be there in the PrefixStore", e.Value)
+		}
+	}
+
+	if _, err := loaded.Put(make([]byte, 129)); err == nil {
+		t.Errorf("maxSize should be preserved across serialization")
+	}
+
+	if _, err := tripod.ReadPrefixStoreRuneTrie(bytes.NewReader(buf.Bytes())); err != tripod.ErrStoreKindMismatch {
+		t.Errorf("reading store of different kind should return ErrStoreKindMismatch, got %v", err)
+	}
+	if _, err := tripod.ReadPrefixStoreByteTrie(bytes.NewReader(buf.Bytes()[:buf.Len()-1])); err == nil {
+		t.Errorf("reading truncated store should return an error")
+	}
+	if _, err := tripod.ReadPrefixStoreByteTrie(bytes.NewReader([]byte("garbage"))); err == nil {
+		t.Errorf("reading garbage should return an error")
+	}
+}
+
+func TestPrefixStoreRuneTrieSerialization(t *testing.T) {
+	tr := tripod.CreatePrefixStoreRuneTrie(128)
+	keys := []string{"café", "cafés", "thé", "日本", "日本語"}
+	for _, key := range keys {
+		tr.Put([]rune(key))
+	}
+
+	var buf bytes.Buffer
+	if _, err := tr.WriteTo(&buf); err != nil {
+		t.Fatalf("serializing should not return an error, got %v", err)
+	}
+
+	loaded, err := tripod.ReadPrefixStoreRuneTrie(&buf)
+	if err != nil {
+		t.Fatalf("reading serialized store should not return an error, got %v", err)
+	}
+	for _, key := range keys {
+		if loaded.Exists([]rune(key)) != true {
+			t.Errorf("key %s should be there in the PrefixStore", key)
+		}
+	}
+	if count := loaded.PrefixSearch([]rune("日本")).Len(); count != 2 {
+		t.Errorf("expected number of elements in trie for given prefix are %d, but there are %d elements", 2, count)
+	}
+}
+
+func TestPrefixStoreSerializationTruncated(t *testing.T) {
+	byteTrie := tripod.CreatePrefixStoreByteTrie(16)
+	runeTrie := tripod.CreatePrefixStoreRuneTrie(16)
+	for _, key := range []string{"go", "gopher", "日本語"} {
+		byteTrie.Put([]byte(key))
+		runeTrie.Put([]rune(key))
+	}
+
+	var byteBuf, runeBuf bytes.Buffer
+	byteTrie.WriteTo(&byteBuf)
+	runeTrie.WriteTo(&runeBuf)
+
+	for i := 0; i < byteBuf.Len(); i++ {
+		if _, err := tripod.ReadPrefixStoreByteTrie(bytes.NewReader(byteBuf.Bytes()[:i])); err == nil {
+			t.Errorf("reading store truncated to %d bytes should return an error", i)
+		}
+	}
+	for i := 0; i < runeBuf.Len(); i++ {
+		if _, err := tripod.ReadPrefixStoreRuneTrie(bytes.NewReader(runeBuf.Bytes()[:i])); err == nil {
+			t.Errorf("reading store truncated to %d bytes should return an error", i)
+		}
+	}
+}
+
+func TestPrefixStoreSerializationHugeMaxKeySize(t *testing.T) {
+	headers := [][]byte{
+		// maxKeySize of 1<<62 and of 1<<64-1, beyond the range of int.
+		[]byte("TRIPODb\x01\x80\x80\x80\x80\x80\x80\x80\x80\x40\x00"),
+		[]byte("TRIPODb\x01\xff\xff\xff\xff\xff\xff\xff\xff\xff\x01\x00"),
+		// uvarint overflowing 64 bits.
+		[]byte("TRIPODb\x01\xff\xff\xff\xff\xff\xff\xff\xff\xff\xff\x01\x00"),
+	}
+	for _, header := range headers {
+		if _, err := tripod.ReadPrefixStoreByteTrie(bytes.NewReader(header)); err == nil {
+			t.Errorf("reading store with huge max size of key %q should return an error", header)
+		}
+		header[6] = 'r'
+		if _, err := tripod.ReadPrefixStoreRuneTrie(bytes.NewReader(header)); err == nil {
+			t.Errorf("reading store with huge max size of key %q should return an error", header)
+		}
+	}
+
+	var buf bytes.Buffer
+	if _, err := tripod.CreatePrefixStoreByteTrie(1 << 30).WriteTo(&buf); err == nil || buf.Len() != 0 {
+		t.Errorf("serializing store with huge max size of key should return an error")
+	}
+}
+
+func TestReadPrefixStore(t *testing.T) {
+	byteTrie := tripod.CreatePrefixStoreByteTrie(16)
+	byteTrie.Put([]byte("go"))
+	runeTrie := tripod.CreatePrefixStoreRuneTrie(16)
+	runeTrie.Put([]rune("日本"))
+
+	var byteBuf, runeBuf bytes.Buffer
+	byteTrie.WriteTo(&byteBuf)
+	runeTrie.WriteTo(&runeBuf)
+
+	bytesRead, runesRead, err := tripod.ReadPrefixStore(&byteBuf)
+	if err != nil || runesRead != nil || bytesRead == nil || !bytesRead.Exists([]byte("go")) {
+		t.Errorf("expected the byte trie to be read, got %v, %v and %v", bytesRead, runesRead, err)
+	}
+	bytesRead, runesRead, err = tripod.ReadPrefixStore(&runeBuf)
+	if err != nil || bytesRead != nil || runesRead == nil || !runesRead.Exists([]rune("日本")) {
+		t.Errorf("expected the rune trie to be read, got %v, %v and %v", bytesRead, runesRead, err)
+	}
+	for _, input := range []string{"", "TRI", "garbage", "TRIPODx\x01"} {
+		if _, _, err := tripod.ReadPrefixStore(strings.NewReader(input)); err == nil {
+			t.Errorf("reading %q should return an error", input)
+		}
+	}
+}
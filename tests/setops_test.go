@@ -0,0 +1,93 @@
+package test_tripod
+
+import (
+	"fmt"
+	"github.com/arpitbbhayani/tripod"
+	"testing"
+)
+
+func createByteTrie(keys ...string) *tripod.PrefixStoreByteTrie {
+	tr := tripod.CreatePrefixStoreByteTrie(16)
+	for _, key := range keys {
+		tr.Put([]byte(key))
+	}
+	return tr
+}
+
+func byteTrieKeys(tr *tripod.PrefixStoreByteTrie) string {
+	var keys []string
+	tr.Range(nil, nil, func(key []byte) bool {
+		keys = append(keys, string(key))
+		return true
+	})
+	return fmt.Sprint(keys)
+}
+
+func TestByteTrieSetOperations(t *testing.T) {
+	base := createByteTrie("go", "gopher", "golang", "rust", "zig")
+	tenant := createByteTrie("go", "gofmt", "gopher", "python")
+
+	testCases := []struct {
+		name     string
+		result   *tripod.PrefixStoreByteTrie
+		expected string
+	}{
+		{"union", tripod.UnionByteTries(base, tenant), "[go gofmt golang gopher python rust zig]"},
+		{"intersection", tripod.IntersectByteTries(base, tenant), "[go gopher]"},
+		{"difference", tripod.DifferenceByteTries(base, tenant), "[golang rust zig]"},
+		{"difference", tripod.DifferenceByteTries(tenant, base), "[gofmt python]"},
+		{"empty intersection", tripod.IntersectByteTries(base, createByteTrie("java")), "[]"},
+		{"difference with itself", tripod.DifferenceByteTries(base, base), "[]"},
+	}
+	for _, tc := range testCases {
+		if keys := byteTrieKeys(tc.result); keys != tc.expected {
+			t.Errorf("%s: expected %s, got %s", tc.name, tc.expected, keys)
+		}
+		if count := tc.result.Count(); count != tc.result.PrefixSearch(nil).Len() {
+			t.Errorf("%s: count %d does not match the keys", tc.name, count)
+		}
+	}
+
+	// The result is a store on its own, sharing no node with the operands.
+	union := tripod.UnionByteTries(base, tenant)
+	union.Put([]byte("gopls"))
+	union.Delete([]byte("rust"))
+	if base.Exists([]byte("gopls")) || !base.Exists([]byte("rust")) {
+		t.Errorf("modifying the result should leave the operands untouched")
+	}
+	if byteTrieKeys(base) != "[go golang gopher rust zig]" || base.Count() != 5 {
+		t.Errorf("operands should be left untouched, got %s", byteTrieKeys(base))
+	}
+}
+
+func TestRuneTrieSetOperations(t *testing.T) {
+	a := tripod.CreatePrefixStoreRuneTrie(8)
+	b := tripod.CreatePrefixStoreRuneTrie(16)
+	for _, key := range []string{"café", "cafés", "thé"} {
+		a.Put([]rune(key))
+	}
+	for _, key := range []string{"café", "crème"} {
+		b.Put([]rune(key))
+	}
+
+	union := tripod.UnionRuneTries(a, b)
+	if union.Count() != 4 || !union.Exists([]rune("crème")) || !union.Exists([]rune("thé")) {
+		t.Errorf("unexpected union of %d keys", union.Count())
+	}
+	if _, err := union.Put([]rune("0123456789abcdef")); err != nil {
+		t.Errorf("union should allow the greater max key size: %s", err)
+	}
+
+	intersection := tripod.IntersectRuneTries(a, b)
+	if intersection.Count() != 1 || !intersection.Exists([]rune("café")) || intersection.Exists([]rune("cafés")) {
+		t.Errorf("unexpected intersection of %d keys", intersection.Count())
+	}
+
+	difference := tripod.DifferenceRuneTries(a, b)
+	if difference.Count() != 2 || difference.Exists([]rune("café")) || !difference.Exists([]rune("cafés")) {
+		t.Errorf("unexpected difference of %d keys", difference.Count())
+	}
+	if rank := difference.Rank([]rune("thé")); rank != 1 {
+		t.Errorf("expected rank %d in difference, got %d", 1, rank)
+	}
+}
@@ -0,0 +1,139 @@
+package test_tripod
+
+import (
+	"github.com/arpitbbhayani/tripod"
+	"sort"
+	"testing"
+)
+
+func byteKeysIterator(keys []string) tripod.ByteKeyIterator {
+	i := 0
+	return func() ([]byte, bool) {
+		if i == len(keys) {
+			return nil, false
+		}
+		i++
+		return []byte(keys[i-1]), true
+	}
+}
+
+func runeKeysIterator(keys []string) tripod.RuneKeyIterator {
+	i := 0
+	return func() ([]rune, bool) {
+		if i == len(keys) {
+			return nil, false
+		}
+		i++
+		return []rune(keys[i-1]), true
+	}
+}
+
+func TestBuildPrefixStoreByteTrieFromSorted(t *testing.T) {
+	keys := []string{"", "go", "go", "gone", "good", "is", "isle"}
+	tr, err := tripod.BuildPrefixStoreByteTrieFromSorted(8, byteKeysIterator(keys))
+	if err != nil {
+		t.Fatalf("building from sorted keys should not return an error, got %v", err)
+	}
+
+	for _, key := range keys[1:] {
+		if tr.Exists([]byte(key)) != true {
+			t.Errorf("key %s should be there in the PrefixStore", key)
+		}
+	}
+	if tr.Exists([]byte("goo")) == true {
+		t.Errorf("fetching non-existent key but for which path exists should return %t", false)
+	}
+	if count := tr.PrefixSearch([]byte("go")).Len(); count != 3 {
+		t.Errorf("expected number of elements in trie for given prefix are %d, but there are %d elements", 3, count)
+	}
+
+	// The built trie should keep working with Put.
+	if newlyAdded, _ := tr.Put([]byte("goner")); newlyAdded == false {
+		t.Errorf("adding key to trie: expected %t", true)
+	}
+
+	if _, err := tripod.BuildPrefixStoreByteTrieFromSorted(8, byteKeysIterator([]string{"go", "apple"})); err == nil {
+		t.Errorf("building from unsorted keys should return an error")
+	}
+	if _, err := tripod.BuildPrefixStoreByteTrieFromSorted(8, byteKeysIterator([]string{"gone", "go"})); err == nil {
+		t.Errorf("building from unsorted keys should return an error")
+	}
+	if _, err := tripod.BuildPrefixStoreByteTrieFromSorted(2, byteKeysIterator([]string{"go", "gone"})); err == nil {
+		t.Errorf("building with keys more than maxSize specified should return an error")
+	}
+}
+
+func TestBuildPrefixStoreByteTrieFromSortedMatchesPut(t *testing.T) {
+	unique := make(map[string]bool)
+	for i := 0; i < 5000; i++ {
+		unique[string(getRandomByteSlice(1+i%16))] = true
+	}
+	keys := make([]string, 0, len(unique))
+	for key := range unique {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	tr, err := tripod.BuildPrefixStoreByteTrieFromSorted(16, byteKeysIterator(keys))
+	if err != nil {
+		t.Fatalf("building from sorted keys should not return an error, got %v", err)
+	}
+	if count := tr.PrefixSearch([]byte("")).Len(); count != len(keys) {
+		t.Errorf("expected elements in trie are %d, but there are %d elements", len(keys), count)
+	}
+	for _, key := range keys {
+		if tr.Exists([]byte(key)) != true {
+			t.Errorf("key %s should be there in the PrefixStore", key)
+		}
+	}
+}
+
+func TestBuildPrefixStoreRuneTrieFromSorted(t *testing.T) {
+	keys := []string{"cafe", "café", "cafés", "thé"}
+	tr, err := tripod.BuildPrefixStoreRuneTrieFromSorted(8, runeKeysIterator(keys))
+	if err != nil {
+		t.Fatalf("building from sorted keys should not return an error, got %v", err)
+	}
+	for _, key := range keys {
+		if tr.Exists([]rune(key)) != true {
+			t.Errorf("key %s should be there in the PrefixStore", key)
+		}
+	}
+	if count := tr.PrefixSearch([]rune("caf")).Len(); count != 3 {
+		t.Errorf("expected number of elements in trie for given prefix are %d, but there are %d elements", 3, count)
+	}
+
+	if _, err := tripod.BuildPrefixStoreRuneTrieFromSorted(8, runeKeysIterator([]string{"thé", "café"})); err == nil {
+		t.Errorf("building from unsorted keys should return an error")
+	}
+}
+
+func TestBuildFromSortedNegativeMaxKeySize(t *testing.T) {
+	tr, err := tripod.BuildPrefixStoreByteTrieFromSorted(-1, byteKeysIterator(nil))
+	if err != nil || tr.Count() != 0 {
+		t.Errorf("building from no keys with negative max size should return an empty trie, got %v", err)
+	}
+	if _, err := tripod.BuildPrefixStoreByteTrieFromSorted(-1, byteKeysIterator([]string{"a"})); err == nil {
+		t.Errorf("building with negative max size should return an error for a non empty key")
+	}
+	if _, err := tripod.BuildPrefixStoreRuneTrieFromSorted(-1, runeKeysIterator([]string{"é"})); err == nil {
+		t.Errorf("building with negative max size should return an error for a non empty key")
+	}
+}
+
+func TestBuildFromSortedThenDelete(t *testing.T) {
+	keys := []string{"go", "gopher", "gophers", "rust"}
+	tr, err := tripod.BuildPrefixStoreByteTrieFromSorted(16, byteKeysIterator(keys))
+	if err != nil {
+		t.Fatalf("building from sorted keys should not return an error, got %v", err)
+	}
+	if tr.Delete([]byte("gophers")) != true || tr.Delete([]byte("rust")) != true {
+		t.Errorf("deleting built keys should return %t", true)
+	}
+	if keys := prefixSearchSorted(tr, ""); len(keys) != 2 || keys[0] != "go" || keys[1] != "gopher" {
+		t.Errorf("unexpected prefix search results %v", keys)
+	}
+	if newlyAdded, _ := tr.Put([]byte("gophers")); newlyAdded == false {
+		t.Errorf("putting deleted key again: expected %t", true)
+	}
+}
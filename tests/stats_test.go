@@ -0,0 +1,124 @@
+package test_tripod
+
+import (
+	"fmt"
+	"github.com/arpitbbhayani/tripod"
+	"math/rand"
+	"testing"
+)
+
+func TestPrefixStoreByteTrieStats(t *testing.T) {
+	tr := tripod.CreatePrefixStoreByteTrie(128)
+	empty := tr.Stats()
+	if empty.KeyCount != 0 || empty.NodeCount != 1 {
+		t.Errorf("empty trie should have %d keys and %d node, got %d and %d", 0, 1, empty.KeyCount, empty.NodeCount)
+	}
+
+	populatePrefixStoreByteTrie(tr)
+	stats := tr.Stats()
+
+	// "te", "test" and "test123" share the path, hence 7 nodes plus the root.
+	if stats.KeyCount != 3 {
+		t.Errorf("expected %d keys, got %d", 3, stats.KeyCount)
+	}
+	if stats.NodeCount != 8 {
+		t.Errorf("expected %d nodes, got %d", 8, stats.NodeCount)
+	}
+	if stats.EstimatedBytes <= empty.EstimatedBytes {
+		t.Errorf("estimated bytes should grow with the trie, got %d <= %d", stats.EstimatedBytes, empty.EstimatedBytes)
+	}
+}
+
+func TestPrefixStoreRuneTrieStats(t *testing.T) {
+	tr := tripod.CreatePrefixStoreRuneTrie(128)
+	tr.Put([]rune("café"))
+	tr.Put([]rune("cafe"))
+
+	stats := tr.Stats()
+	if stats.KeyCount != 2 {
+		t.Errorf("expected %d keys, got %d", 2, stats.KeyCount)
+	}
+	if stats.NodeCount != 6 {
+		t.Errorf("expected %d nodes, got %d", 6, stats.NodeCount)
+	}
+}
+
+func TestPrefixStoreStatsShape(t *testing.T) {
+	tr := tripod.CreatePrefixStoreByteTrie(128)
+	for _, key := range []string{"a", "ab", "ac", "abcd"} {
+		tr.Put([]byte(key))
+	}
+
+	stats := tr.Stats()
+	if stats.MaxDepth != 4 {
+		t.Errorf("expected max depth %d, got %d", 4, stats.MaxDepth)
+	}
+	if stats.AvgDepth != 2.25 {
+		t.Errorf("expected avg depth %f, got %f", 2.25, stats.AvgDepth)
+	}
+
+	// The leaves "ac" and "abcd", the nodes "root", "ab" and "abc" with a
+	// single child, and "a" with two.
+	expected := map[int]int{0: 2, 1: 3, 2: 1}
+	if len(stats.ChildrenHistogram) != len(expected) {
+		t.Fatalf("expected histogram %v, got %v", expected, stats.ChildrenHistogram)
+	}
+	for children, nodes := range expected {
+		if stats.ChildrenHistogram[children] != nodes {
+			t.Errorf("expected histogram %v, got %v", expected, stats.ChildrenHistogram)
+			break
+		}
+	}
+
+	if stats.NodeBytes <= 0 || stats.MapBytes <= 0 || stats.EstimatedBytes != stats.NodeBytes+stats.MapBytes {
+		t.Errorf("estimated bytes should be the sum of node and map bytes, got %d, %d and %d",
+			stats.NodeBytes, stats.MapBytes, stats.EstimatedBytes)
+	}
+
+	empty := tripod.CreatePrefixStoreRuneTrie(8).Stats()
+	if empty.MaxDepth != 0 || empty.AvgDepth != 0 {
+		t.Errorf("empty trie should have no depth, got %d and %f", empty.MaxDepth, empty.AvgDepth)
+	}
+}
+
+func checkSummary(t *testing.T, name string, summary tripod.PrefixStoreSummary, stats tripod.PrefixStoreStats) {
+	if summary.KeyCount != stats.KeyCount || summary.NodeCount != stats.NodeCount {
+		t.Fatalf("%s: expected summary of %d keys and %d nodes, got %d and %d",
+			name, stats.KeyCount, stats.NodeCount, summary.KeyCount, summary.NodeCount)
+	}
+	if summary.EstimatedBytes <= 0 {
+		t.Fatalf("%s: expected positive estimated bytes, got %d", name, summary.EstimatedBytes)
+	}
+}
+
+func TestPrefixStoreSummary(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	byteTrie := tripod.CreatePrefixStoreByteTrie(8)
+	runeTrie := tripod.CreatePrefixStoreRuneTrie(8)
+	for i := 0; i < 2000; i++ {
+		key := fmt.Sprintf("%d%d%d%d", r.Intn(3), r.Intn(3), r.Intn(3), r.Intn(3))[:1+r.Intn(4)]
+		if r.Intn(3) == 0 {
+			byteTrie.Delete([]byte(key))
+			runeTrie.Delete([]rune(key))
+		} else {
+			byteTrie.Put([]byte(key))
+			runeTrie.Put([]rune(key))
+		}
+		checkSummary(t, "PrefixStoreByteTrie", byteTrie.Summary(), byteTrie.Stats())
+		checkSummary(t, "PrefixStoreRuneTrie", runeTrie.Summary(), runeTrie.Stats())
+	}
+
+	keys := []string{"a", "ab", "abc", "abd", "b", "bcd"}
+	built, _ := tripod.BuildPrefixStoreByteTrieFromSorted(8, byteKeysIterator(keys))
+	checkSummary(t, "BuildPrefixStoreByteTrieFromSorted", built.Summary(), built.Stats())
+	built.Put([]byte("abcd"))
+	built.Delete([]byte("bcd"))
+	checkSummary(t, "BuildPrefixStoreByteTrieFromSorted", built.Summary(), built.Stats())
+	builtRunes, _ := tripod.BuildPrefixStoreRuneTrieFromSorted(8, runeKeysIterator(keys))
+	checkSummary(t, "BuildPrefixStoreRuneTrieFromSorted", builtRunes.Summary(), builtRunes.Stats())
+
+	union := tripod.UnionByteTries(built, byteTrie)
+	checkSummary(t, "UnionByteTries", union.Summary(), union.Stats())
+	difference := tripod.DifferenceRuneTries(runeTrie, builtRunes)
+	checkSummary(t, "DifferenceRuneTries", difference.Summary(), difference.Stats())
+}
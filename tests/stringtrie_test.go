@@ -0,0 +1,129 @@
+package test_tripod
+
+import (
+	"fmt"
+	"github.com/arpitbbhayani/tripod"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestPrefixStoreStringPut(t *testing.T) {
+	tr := tripod.CreatePrefixStoreString(4)
+
+	if _, err := tr.Put("cafés"); err == nil {
+		t.Errorf("adding data more than maxSize specified should return an error")
+	}
+
+	// 4 runes but 5 bytes, hence within maxSize.
+	if newlyAdded, _ := tr.Put("café"); newlyAdded == false {
+		t.Errorf("adding key to trie: expected %t", true)
+	}
+
+	if newlyAdded, _ := tr.Put("café"); newlyAdded == true {
+		t.Errorf("readding same key to trie: expected %t", false)
+	}
+
+	if newlyAdded, _ := tr.Put(""); newlyAdded == true {
+		t.Errorf("adding empty string to trie, expected %t", false)
+	}
+
+	if _, err := tr.Put("caf\xc3"); err == nil {
+		t.Errorf("adding invalid UTF-8 should return an error")
+	}
+}
+
+func TestPrefixStoreStringExists(t *testing.T) {
+	tr := tripod.CreatePrefixStoreString(128)
+	for key := range dataset {
+		tr.Put(key)
+	}
+
+	if isPresent := tr.Exists(""); isPresent == true {
+		t.Errorf("fetching empty key from trie should return false")
+	}
+
+	if isPresent := tr.Exists("tes"); isPresent == true {
+		t.Errorf("fetching non-existent key but for which path exists should return %t", false)
+	}
+
+	for key := range dataset {
+		if isPresent := tr.Exists(key); isPresent == false {
+			t.Errorf("fetching existing key from trie should return %t", true)
+		}
+	}
+}
+
+func TestPrefixStoreStringPrefixSearch(t *testing.T) {
+	tr := tripod.CreatePrefixStoreString(16)
+	for _, key := range []string{"thé", "the", "théâtre", "therapy", "日本", "日本語"} {
+		tr.Put(key)
+	}
+
+	if results := strings.Join(tr.PrefixSearch("th"), ","); results != "the,therapy,thé,théâtre" {
+		t.Errorf("expected sorted keys for prefix, got %s", results)
+	}
+
+	if results := strings.Join(tr.PrefixSearch("thé"), ","); results != "thé,théâtre" {
+		t.Errorf("expected keys for prefix ending in a multi-byte rune, got %s", results)
+	}
+
+	// "日" is e6 97 a5 and "本" is e6 9c ac; a prefix ending in the middle of
+	// a rune should match nothing.
+	if results := tr.PrefixSearch("日\xe6"); len(results) != 0 {
+		t.Errorf("prefix with incomplete rune should match nothing, got %v", results)
+	}
+
+	if count := len(tr.PrefixSearch("")); count != 6 {
+		t.Errorf("expected elements in trie are %d, but there are %d elements", 6, count)
+	}
+
+	var firstTwo []string
+	tr.PrefixSearchFunc("", func(key string) bool {
+		firstTwo = append(firstTwo, key)
+		return len(firstTwo) < 2
+	})
+	if strings.Join(firstTwo, ",") != "the,therapy" {
+		t.Errorf("PrefixSearchFunc should stop when asked to, got %v", firstTwo)
+	}
+
+	if tr.Delete("the") != true || tr.Exists("therapy") != true || tr.Count() != 5 {
+		t.Errorf("deleting a key should not affect the keys it is prefix of")
+	}
+}
+
+// Puts and deletes random keys through put and del, and checks after every
+// call that count agrees with the keys present, and at the end that keys, if
+// not nil, enumerates as many keys.
+func exerciseCount(t *testing.T, name string, put func(key string) bool, del func(key string) bool, count func() int, keys func() int) {
+	r := rand.New(rand.NewSource(1))
+	present := make(map[string]bool)
+	for i := 0; i < 2000; i++ {
+		key := fmt.Sprintf("%d/%d/%d", r.Intn(4), r.Intn(4), r.Intn(8))[:1+2*r.Intn(3)]
+		if r.Intn(3) == 0 {
+			if del(key) != present[key] {
+				t.Fatalf("%s: deleting %s: expected %t", name, key, present[key])
+			}
+			delete(present, key)
+		} else {
+			if put(key) == present[key] {
+				t.Fatalf("%s: adding %s: expected %t", name, key, !present[key])
+			}
+			present[key] = true
+		}
+		if count() != len(present) {
+			t.Fatalf("%s: expected %d keys, got %d", name, len(present), count())
+		}
+	}
+	if keys != nil && keys() != len(present) {
+		t.Errorf("%s: expected %d keys enumerated, got %d", name, len(present), keys())
+	}
+}
+
+func TestPrefixStoreStringCountAfterChurn(t *testing.T) {
+	tr := tripod.CreatePrefixStoreString(16)
+	exerciseCount(t, "PrefixStoreString",
+		func(key string) bool { added, _ := tr.Put(key); return added },
+		tr.Delete, tr.Count,
+		func() int { return len(tr.PrefixSearch("")) })
+}
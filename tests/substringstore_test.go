@@ -0,0 +1,99 @@
+package test_tripod
+
+import (
+	"fmt"
+	"github.com/arpitbbhayani/tripod"
+	"testing"
+)
+
+func containsSearchResults(s *tripod.SubstringStore, fragment string) []string {
+	var results []string
+	for e := s.ContainsSearch([]rune(fragment)).Front(); e != nil; e = e.Next() {
+		results = append(results, string(e.Value.([]rune)))
+	}
+	return results
+}
+
+func TestSubstringStorePut(t *testing.T) {
+	s := tripod.CreateSubstringStore(8)
+
+	if _, err := s.Put([]rune("iphone case")); err == nil {
+		t.Errorf("adding data more than maxSize specified should return an error")
+	}
+	if newlyAdded, _ := s.Put([]rune("")); newlyAdded == true {
+		t.Errorf("adding empty string to store, expected %t", false)
+	}
+	if newlyAdded, _ := s.Put([]rune("phone")); newlyAdded == false {
+		t.Errorf("adding key to store: expected %t", true)
+	}
+	if newlyAdded, _ := s.Put([]rune("phone")); newlyAdded == true {
+		t.Errorf("readding same key to store: expected %t", false)
+	}
+	if s.Exists([]rune("phone")) != true || s.Exists([]rune("hone")) == true {
+		t.Errorf("Exists should match whole keys only")
+	}
+}
+
+func TestSubstringStoreContainsSearch(t *testing.T) {
+	s := tripod.CreateSubstringStore(32)
+	for _, key := range []string{"iphone case", "phone", "headphones", "saxophone", "café au lait", "banana"} {
+		s.Put([]rune(key))
+	}
+
+	expected := "[iphone case phone headphones saxophone]"
+	if results := containsSearchResults(s, "phone"); len(results) != 4 || fmt.Sprint(results) != expected {
+		t.Errorf("expected %s, got %v", expected, results)
+	}
+
+	// "ana" occurs twice in "banana", yet the key is returned once.
+	if results := containsSearchResults(s, "ana"); len(results) != 1 {
+		t.Errorf("expected key to be returned once, got %v", results)
+	}
+
+	if results := containsSearchResults(s, "é a"); len(results) != 1 || results[0] != "café au lait" {
+		t.Errorf("expected fragment spanning multi-byte runes to match, got %v", results)
+	}
+
+	if results := containsSearchResults(s, "phonex"); len(results) != 0 {
+		t.Errorf("expected no key for absent fragment, got %v", results)
+	}
+
+	if count := len(containsSearchResults(s, "")); count != 6 {
+		t.Errorf("empty fragment should match all %d keys, got %d", 6, count)
+	}
+
+	if s.Delete([]rune("phone")) != true || s.Delete([]rune("phone")) == true {
+		t.Errorf("deleting existing key should return %t once", true)
+	}
+	if results := containsSearchResults(s, "phone"); len(results) != 3 {
+		t.Errorf("deleted key should not be returned, got %v", results)
+	}
+	if s.Count() != 5 {
+		t.Errorf("expected %d keys after deletion, got %d", 5, s.Count())
+	}
+}
+
+func TestSubstringStoreChurn(t *testing.T) {
+	s := tripod.CreateSubstringStore(16)
+	for i := 0; i < 1000; i++ {
+		key := []rune(fmt.Sprintf("key%d", i))
+		s.Put(key)
+		if i >= 2 {
+			s.Delete([]rune(fmt.Sprintf("key%d", i-2)))
+		}
+	}
+	if s.Count() != 2 {
+		t.Errorf("expected %d keys, got %d", 2, s.Count())
+	}
+
+	// Deleted ids are reused, yet keys are returned in the order they were
+	// put.
+	s.Put([]rune("key0"))
+	keys := containsSearchResults(s, "key")
+	if fmt.Sprint(keys) != "[key998 key999 key0]" {
+		t.Errorf("expected keys in the order they were put, got %v", keys)
+	}
+	if results := containsSearchResults(s, "y5"); len(results) != 0 {
+		t.Errorf("expected deleted keys not to be found, got %v", results)
+	}
+}
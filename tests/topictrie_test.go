@@ -0,0 +1,93 @@
+package test_tripod
+
+import (
+	"fmt"
+	"github.com/arpitbbhayani/tripod"
+	"sort"
+	"testing"
+)
+
+func matchTopic(tr *tripod.TopicTrie, topic string) string {
+	filters := tr.Match(topic)
+	sort.Strings(filters)
+	return fmt.Sprint(filters)
+}
+
+func TestTopicTrieSubscribe(t *testing.T) {
+	tr := tripod.CreateTopicTrie()
+
+	if newlyAdded, _ := tr.Subscribe("sport/tennis/+"); newlyAdded == false {
+		t.Errorf("subscribing filter: expected %t", true)
+	}
+	if newlyAdded, _ := tr.Subscribe("sport/tennis/+"); newlyAdded == true {
+		t.Errorf("subscribing existing filter: expected %t", false)
+	}
+
+	for _, filter := range []string{"", "sport/#/ranking", "sport/tennis#", "sport+", "#/x"} {
+		if _, err := tr.Subscribe(filter); err == nil {
+			t.Errorf("subscribing invalid filter %q should return an error", filter)
+		}
+	}
+
+	if tr.Unsubscribe("sport/tennis") == true {
+		t.Errorf("unsubscribing missing filter should return %t", false)
+	}
+	if tr.Unsubscribe("sport/tennis/+") != true || tr.Count() != 0 {
+		t.Errorf("unsubscribing existing filter should return %t", true)
+	}
+}
+
+func TestTopicTrieMatch(t *testing.T) {
+	tr := tripod.CreateTopicTrie()
+	filters := []string{
+		"sport/tennis/player1",
+		"sport/tennis/+",
+		"sport/+/player1",
+		"sport/#",
+		"#",
+		"+/+",
+		"+/tennis/#",
+		"finance/#",
+		"$SYS/#",
+		"/+",
+	}
+	for _, filter := range filters {
+		if _, err := tr.Subscribe(filter); err != nil {
+			t.Fatalf("subscribing %q: %s", filter, err)
+		}
+	}
+
+	testCases := []struct {
+		topic    string
+		expected string
+	}{
+		{"sport/tennis/player1", "[# +/tennis/# sport/# sport/+/player1 sport/tennis/+ sport/tennis/player1]"},
+		{"sport/tennis/player1/ranking", "[# +/tennis/# sport/#]"},
+		{"sport", "[# sport/#]"},
+		{"sport/tennis", "[# +/+ +/tennis/# sport/#]"},
+		{"finance", "[# finance/#]"},
+		{"/finance", "[# +/+ /+]"},
+		{"$SYS/uptime", "[$SYS/#]"},
+		{"$SYS", "[$SYS/#]"},
+		{"sport/+", "[]"},
+		{"", "[]"},
+	}
+	for _, tc := range testCases {
+		if matched := matchTopic(tr, tc.topic); matched != tc.expected {
+			t.Errorf("topic %q: expected %s, got %s", tc.topic, tc.expected, matched)
+		}
+	}
+
+	tr.Unsubscribe("#")
+	tr.Unsubscribe("sport/#")
+	if matched := matchTopic(tr, "sport"); matched != "[]" {
+		t.Errorf("expected no match after unsubscribing, got %s", matched)
+	}
+}
+
+func TestTopicTrieCountAfterChurn(t *testing.T) {
+	tr := tripod.CreateTopicTrie()
+	exerciseCount(t, "TopicTrie",
+		func(key string) bool { added, _ := tr.Subscribe(key); return added },
+		tr.Unsubscribe, tr.Count, nil)
+}
@@ -0,0 +1,84 @@
+package test_tripod
+
+import (
+	"fmt"
+	"github.com/arpitbbhayani/tripod"
+	"testing"
+)
+
+func TestPrefixStoreByteTrieTopPrefixes(t *testing.T) {
+	tr := createByteTrie("a", "apple", "apricot", "avocado", "banana", "band", "bandana", "cherry", "date", "dates")
+
+	var top []string
+	for _, p := range tr.TopPrefixes(2, 3) {
+		top = append(top, fmt.Sprintf("%s:%d", p.Prefix, p.Count))
+	}
+	// "a" is shorter than the depth and is not counted, and "ap" comes
+	// before "da" on their tie.
+	expected := "[ba:3 ap:2 da:2]"
+	if fmt.Sprint(top) != expected {
+		t.Errorf("expected top prefixes %s, got %v", expected, top)
+	}
+
+	top = nil
+	for _, p := range tr.TopPrefixes(1, 10) {
+		top = append(top, fmt.Sprintf("%s:%d", p.Prefix, p.Count))
+	}
+	expected = "[a:4 b:3 d:2 c:1]"
+	if fmt.Sprint(top) != expected {
+		t.Errorf("expected top prefixes %s, got %v", expected, top)
+	}
+
+	if len(tr.TopPrefixes(10, 3)) != 0 || len(tr.TopPrefixes(0, 3)) != 0 || len(tr.TopPrefixes(1, 0)) != 0 {
+		t.Errorf("expected no top prefixes")
+	}
+}
+
+func TestPrefixStoreRuneTrieTopPrefixes(t *testing.T) {
+	tr := tripod.CreatePrefixStoreRuneTrie(16)
+	for _, key := range []string{"été", "étoile", "étude", "eau", "écho", "zèbre"} {
+		tr.Put([]rune(key))
+	}
+
+	top := tr.TopPrefixes(2, 2)
+	if len(top) != 2 || string(top[0].Prefix) != "ét" || top[0].Count != 3 || string(top[1].Prefix) != "ea" || top[1].Count != 1 {
+		t.Errorf("unexpected top prefixes %v", top)
+	}
+}
+
+func TestPrefixStoreByteTrieSplitPoints(t *testing.T) {
+	tr := tripod.CreatePrefixStoreByteTrie(8)
+	for i := 0; i < 100; i++ {
+		tr.Put([]byte(fmt.Sprintf("k%03d", i)))
+	}
+
+	points := tr.SplitPoints(4)
+	if len(points) != 3 || string(points[0]) != "k025" || string(points[1]) != "k050" || string(points[2]) != "k075" {
+		t.Errorf("unexpected split points %q", points)
+	}
+	for i, p := range points {
+		if rank := tr.Rank(p); rank != (i+1)*25 {
+			t.Errorf("expected group %d to hold %d keys, got %d", i, 25, rank-i*25)
+		}
+	}
+
+	small := createByteTrie("a", "b")
+	if points := small.SplitPoints(5); len(points) != 1 || string(points[0]) != "b" {
+		t.Errorf("expected a single split point with 2 keys, got %q", points)
+	}
+	if points := small.SplitPoints(1); len(points) != 0 {
+		t.Errorf("expected no split point for a single group, got %q", points)
+	}
+}
+
+func TestPrefixStoreRuneTrieSplitPoints(t *testing.T) {
+	tr := tripod.CreatePrefixStoreRuneTrie(8)
+	for _, key := range []string{"α", "β", "γ", "δ", "ε", "ζ"} {
+		tr.Put([]rune(key))
+	}
+
+	points := tr.SplitPoints(3)
+	if len(points) != 2 || string(points[0]) != "γ" || string(points[1]) != "ε" {
+		t.Errorf("unexpected split points %q", points)
+	}
+}
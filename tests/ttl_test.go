@@ -0,0 +1,169 @@
+package test_tripod
+
+import (
+	"github.com/arpitbbhayani/tripod"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	tickers int
+
+	// Ticks of every ticker, delivered by Tick only.
+	ticks chan time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func (c *fakeClock) NewTicker(d time.Duration) (<-chan time.Time, func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tickers++
+	return c.ticks, func() {}
+}
+
+// Delivers a tick, waiting for a ticker to receive it.
+func (c *fakeClock) Tick() { c.ticks <- c.Now() }
+
+func TestTTLPrefixStore(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	s := tripod.CreateTTLPrefixStore(16, tripod.TTLOptions{TTL: time.Minute, Clock: clock})
+
+	s.Put([]byte("golang"))
+	s.PutWithTTL([]byte("gopher"), 2*time.Minute)
+	s.PutWithTTL([]byte("go"), 0)
+
+	clock.Advance(time.Minute)
+	if s.Exists([]byte("golang")) {
+		t.Errorf("key past its expiry should not exist")
+	}
+	if !s.Exists([]byte("gopher")) || !s.Exists([]byte("go")) {
+		t.Errorf("keys not expired should exist")
+	}
+	var keys []string
+	for e := s.PrefixSearch([]byte("go")).Front(); e != nil; e = e.Next() {
+		keys = append(keys, string(e.Value.([]byte)))
+	}
+	sort.Strings(keys)
+	if len(keys) != 2 || keys[0] != "go" || keys[1] != "gopher" {
+		t.Errorf("unexpected prefix search results %v", keys)
+	}
+	if s.Count() != 2 {
+		t.Errorf("expected %d keys, got %d", 2, s.Count())
+	}
+	if s.Delete([]byte("golang")) {
+		t.Errorf("deleting expired key should return %t", false)
+	}
+
+	// Putting a key again resets its expiry.
+	s.Put([]byte("gopher"))
+	clock.Advance(59 * time.Second)
+	if expiresAt, ok := s.ExpiresAt([]byte("gopher")); !ok || !expiresAt.Equal(time.Unix(120, 0)) {
+		t.Errorf("expected key to expire at %v, got %v", time.Unix(120, 0), expiresAt)
+	}
+	if _, ok := s.ExpiresAt([]byte("go")); ok {
+		t.Errorf("key put without time to live should not expire")
+	}
+
+	clock.Advance(time.Second)
+	if newlyAdded, _ := s.Put([]byte("gopher")); newlyAdded == false {
+		t.Errorf("putting expired key: expected %t", true)
+	}
+	if newlyAdded, _ := s.PutWithTTL([]byte("go"), time.Second); newlyAdded == true {
+		t.Errorf("putting present key: expected %t", false)
+	}
+	if _, err := s.Put([]byte("a key way too long for the store")); err == nil {
+		t.Errorf("expected error for key longer than the maximum size")
+	}
+}
+
+func TestTTLPrefixStoreSweep(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	s := tripod.CreateTTLPrefixStore(16, tripod.TTLOptions{TTL: time.Second, Clock: clock})
+	for _, key := range []string{"a", "ab", "abc", "b"} {
+		s.Put([]byte(key))
+	}
+	s.PutWithTTL([]byte("c"), time.Hour)
+
+	if swept := s.Sweep(); swept != 0 {
+		t.Errorf("expected %d keys swept, got %d", 0, swept)
+	}
+	clock.Advance(time.Second)
+	if swept := s.Sweep(); swept != 4 {
+		t.Errorf("expected %d keys swept, got %d", 4, swept)
+	}
+	if !s.Exists([]byte("c")) || s.Count() != 1 {
+		t.Errorf("expected only the key not expired to remain")
+	}
+}
+
+func TestTTLPrefixStoreAmortizedSweep(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	s := tripod.CreateTTLPrefixStore(16, tripod.TTLOptions{TTL: time.Second, Clock: clock})
+	for i := 0; i < 100; i++ {
+		s.Put([]byte{byte(i)})
+	}
+	clock.Advance(time.Second)
+
+	// Every write reclaims a bounded number of expired keys, hence enough
+	// writes reclaim them all without an explicit sweep.
+	for i := 0; i < 100; i++ {
+		s.PutWithTTL([]byte{byte(200)}, 0)
+	}
+	if swept := s.Sweep(); swept != 0 {
+		t.Errorf("expected expired keys to be reclaimed by writes, %d were left", swept)
+	}
+}
+
+func TestTTLPrefixStoreSweeper(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0), ticks: make(chan time.Time)}
+	s := tripod.CreateTTLPrefixStore(16, tripod.TTLOptions{TTL: time.Second, Clock: clock})
+	s.Put([]byte("a"))
+	s.Put([]byte("b"))
+
+	stop := s.StartSweeper(time.Minute)
+	clock.Advance(time.Second)
+
+	// The sweeper receives the tick before stopping, and stop waits for it
+	// to return, hence for the sweep to be done.
+	clock.Tick()
+	stop()
+	stop()
+
+	if swept := s.Sweep(); swept != 0 {
+		t.Errorf("expected expired keys to be reclaimed by the sweeper, %d were left", swept)
+	}
+}
+
+func TestTTLPrefixStoreSweeperNonPositiveInterval(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0), ticks: make(chan time.Time)}
+	s := tripod.CreateTTLPrefixStore(16, tripod.TTLOptions{TTL: time.Second, Clock: clock})
+	s.Put([]byte("a"))
+
+	for _, interval := range []time.Duration{0, -time.Second} {
+		stop := s.StartSweeper(interval)
+		stop()
+	}
+	if clock.tickers != 0 {
+		t.Errorf("expected %d tickers for non positive intervals, got %d", 0, clock.tickers)
+	}
+
+	clock.Advance(time.Second)
+	if swept := s.Sweep(); swept != 1 {
+		t.Errorf("expected %d keys swept, got %d", 1, swept)
+	}
+}
@@ -0,0 +1,74 @@
+package test_tripod
+
+import (
+	"fmt"
+	"github.com/arpitbbhayani/tripod"
+	"testing"
+)
+
+func TestPrefixStoreByteTrieWalk(t *testing.T) {
+	tr := createByteTrie("go", "gopher", "gone", "rust")
+
+	var visited []string
+	tr.Walk(nil, func(path []byte, isKey bool) tripod.WalkAction {
+		if isKey {
+			visited = append(visited, string(path)+"*")
+		} else {
+			visited = append(visited, string(path))
+		}
+		return tripod.WalkContinue
+	})
+	expected := "[ g go* gon gone* gop goph gophe gopher* r ru rus rust*]"
+	if fmt.Sprint(visited) != expected {
+		t.Errorf("expected nodes %s, got %v", expected, visited)
+	}
+
+	visited = nil
+	tr.Walk([]byte("go"), func(path []byte, isKey bool) tripod.WalkAction {
+		visited = append(visited, string(path))
+		if len(path) == 3 {
+			return tripod.WalkSkipChildren
+		}
+		return tripod.WalkContinue
+	})
+	if fmt.Sprint(visited) != "[go gon gop]" {
+		t.Errorf("expected children to be skipped, got %v", visited)
+	}
+
+	visited = nil
+	tr.Walk(nil, func(path []byte, isKey bool) tripod.WalkAction {
+		visited = append(visited, string(path))
+		if isKey {
+			return tripod.WalkStop
+		}
+		return tripod.WalkContinue
+	})
+	if fmt.Sprint(visited) != "[ g go]" {
+		t.Errorf("expected walk to stop at the first key, got %v", visited)
+	}
+
+	tr.Walk([]byte("java"), func(path []byte, isKey bool) tripod.WalkAction {
+		t.Errorf("walking a missing prefix should visit nothing, visited %q", path)
+		return tripod.WalkContinue
+	})
+}
+
+func TestPrefixStoreRuneTrieWalk(t *testing.T) {
+	tr := tripod.CreatePrefixStoreRuneTrie(16)
+	for _, key := range []string{"thé", "the", "theme", "them"} {
+		tr.Put([]rune(key))
+	}
+
+	// Branching factor of every node: the number of nodes whose path
+	// extends it by a single rune.
+	children := make(map[string]int)
+	tr.Walk([]rune("th"), func(path []rune, isKey bool) tripod.WalkAction {
+		if len(path) > 2 {
+			children[string(path[:len(path)-1])]++
+		}
+		return tripod.WalkContinue
+	})
+	if children["th"] != 2 || children["the"] != 1 || children["them"] != 1 || len(children) != 3 {
+		t.Errorf("unexpected branching factors %v", children)
+	}
+}
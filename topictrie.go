@@ -0,0 +1,148 @@
+package tripod
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	topicSeparator      = "/"
+	topicSingleWildcard = "+"
+	topicMultiWildcard  = "#"
+)
+
+// Represents a store of MQTT topic filters (subscriptions), which uses an
+// in-memory trie whose edges are the levels of the filters, like PathStore
+// does with the separator "/". Filters may contain the wildcards "+", which
+// matches a single level, and "#", which matches any number of levels,
+// including none, and must be the last level.
+// Match returns the filters matching a concrete topic, visiting only the
+// branches of the trie that can match it, hence independently of the number
+// of filters stored.
+type TopicTrie struct {
+	root *pathNode
+
+	// Number of filters present, maintained by Subscribe and Unsubscribe.
+	count int
+}
+
+// Creates and returns reference to a new instance of TopicTrie.
+func CreateTopicTrie() *TopicTrie {
+	return &TopicTrie{root: &pathNode{}}
+}
+
+// Checks and returns an error if filter is not a valid MQTT topic filter.
+func validateTopicFilter(filter string) error {
+	if len(filter) == 0 {
+		return fmt.Errorf("topic filter should not be empty")
+	}
+	for rest, more := filter, true; more; {
+		var level string
+		level, rest, more = strings.Cut(rest, topicSeparator)
+		if level == topicMultiWildcard && more {
+			return fmt.Errorf("wildcard %q should be the last level of topic filter %q", topicMultiWildcard, filter)
+		}
+		if len(level) > 1 && strings.ContainsAny(level, topicSingleWildcard+topicMultiWildcard) {
+			return fmt.Errorf("wildcards should occupy a whole level of topic filter %q", filter)
+		}
+	}
+	return nil
+}
+
+// Adds the filter to the trie and returns if filter was succesfully added and
+// any error encountered.
+// A non nil error is returned if filter is not a valid MQTT topic filter.
+func (t *TopicTrie) Subscribe(filter string) (bool, error) {
+	if err := validateTopicFilter(filter); err != nil {
+		return false, err
+	}
+
+	current_node := t.root
+	for rest, more := filter, true; more; {
+		var level string
+		level, rest, more = strings.Cut(rest, topicSeparator)
+		current_node = current_node.child(level)
+	}
+
+	if current_node.isLast {
+		return false, nil
+	}
+	current_node.isLast = true
+	t.count++
+	return true, nil
+}
+
+// Removes the filter from the trie and returns if the filter was present.
+// Nodes that are left with no filter under them are pruned.
+func (t *TopicTrie) Unsubscribe(filter string) bool {
+	if len(filter) == 0 {
+		return false
+	}
+	deleted, _ := t.root.delete(filter, topicSeparator)
+	if deleted {
+		t.count--
+	}
+	return deleted
+}
+
+// Returns the number of filters present in the trie.
+func (t *TopicTrie) Count() int {
+	return t.count
+}
+
+// Returns every stored filter matching the concrete topic, in no particular
+// order. Topics containing wildcards match nothing. As per MQTT, a topic
+// starting with "$", like "$SYS/uptime", is not matched by the filters
+// starting with a wildcard.
+func (t *TopicTrie) Match(topic string) []string {
+	if len(topic) == 0 || strings.ContainsAny(topic, topicSingleWildcard+topicMultiWildcard) {
+		return nil
+	}
+
+	var entries []string
+	t.root.matchTopic(topic, true, make([]byte, 0, len(topic)+2), func(filter string) {
+		entries = append(entries, filter)
+	})
+	return entries
+}
+
+// Recursively calls fn with every filter under n matching topic, with buffer
+// holding the filter levels leading to n. first is true at the root, where
+// the wildcards do not match the levels starting with "$".
+func (n *pathNode) matchTopic(topic string, first bool, buffer []byte, fn func(filter string)) {
+	level, rest, more := strings.Cut(topic, topicSeparator)
+	if !first {
+		buffer = append(buffer, topicSeparator...)
+	}
+	wildcards := !first || !strings.HasPrefix(level, "$")
+
+	if wildcards {
+		if child := n.children[topicMultiWildcard]; child != nil && child.isLast {
+			fn(string(append(buffer, topicMultiWildcard...)))
+		}
+	}
+
+	visit := func(child *pathNode, edge string) {
+		next := append(buffer, edge...)
+		if more {
+			child.matchTopic(rest, false, next, fn)
+			return
+		}
+		if child.isLast {
+			fn(string(next))
+		}
+		// "#" also matches the parent level, e.g. "sport/#" matches "sport".
+		if multi := child.children[topicMultiWildcard]; multi != nil && multi.isLast {
+			fn(string(append(append(next, topicSeparator...), topicMultiWildcard...)))
+		}
+	}
+
+	if child := n.children[level]; child != nil {
+		visit(child, level)
+	}
+	if wildcards {
+		if child := n.children[topicSingleWildcard]; child != nil {
+			visit(child, topicSingleWildcard)
+		}
+	}
+}
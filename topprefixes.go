@@ -0,0 +1,189 @@
+package tripod
+
+import (
+	"bytes"
+	"container/heap"
+	"slices"
+	"sort"
+)
+
+// Represents a prefix of a PrefixStoreByteTrie along with the number of keys
+// having it as a prefix.
+type BytePrefixCount struct {
+	Prefix []byte
+	Count  int
+}
+
+// Represents a prefix of a PrefixStoreRuneTrie along with the number of keys
+// having it as a prefix.
+type RunePrefixCount struct {
+	Prefix []rune
+	Count  int
+}
+
+// A min-heap of prefix counts, the smallest count being at the top and, among
+// equal counts, the greatest prefix, so that the top is the first to be
+// evicted when keeping the k prefixes with the most keys.
+type bytePrefixCountHeap []BytePrefixCount
+
+func (h bytePrefixCountHeap) Len() int { return len(h) }
+func (h bytePrefixCountHeap) Less(i, j int) bool {
+	if h[i].Count != h[j].Count {
+		return h[i].Count < h[j].Count
+	}
+	return bytes.Compare(h[i].Prefix, h[j].Prefix) > 0
+}
+func (h bytePrefixCountHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *bytePrefixCountHeap) Push(x interface{}) { *h = append(*h, x.(BytePrefixCount)) }
+func (h *bytePrefixCountHeap) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+type runePrefixCountHeap []RunePrefixCount
+
+func (h runePrefixCountHeap) Len() int { return len(h) }
+func (h runePrefixCountHeap) Less(i, j int) bool {
+	if h[i].Count != h[j].Count {
+		return h[i].Count < h[j].Count
+	}
+	return slices.Compare(h[i].Prefix, h[j].Prefix) > 0
+}
+func (h runePrefixCountHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *runePrefixCountHeap) Push(x interface{}) { *h = append(*h, x.(RunePrefixCount)) }
+func (h *runePrefixCountHeap) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// Returns the k prefixes of length depth having the most keys under them,
+// from the most to the least keys, and in ascending order of prefix among
+// equal counts. Keys shorter than depth have no such prefix and are not
+// counted.
+// The subtree counts kept by the nodes bound the count of every prefix
+// below a node, hence the subtrees that cannot make it to the top k are not
+// visited.
+func (t *PrefixStoreByteTrie) TopPrefixes(depth, k int) []BytePrefixCount {
+	if depth <= 0 || k <= 0 {
+		return nil
+	}
+
+	h := make(bytePrefixCountHeap, 0, k)
+	buffer := make([]byte, 0, depth)
+	var visit func(node *PrefixStoreByteTrie)
+	visit = func(node *PrefixStoreByteTrie) {
+		if len(h) == k && node.count <= h[0].Count {
+			// The prefixes below node do not have more keys than the
+			// least of the top k.
+			return
+		}
+		if len(buffer) == depth {
+			entry := BytePrefixCount{Prefix: slices.Clone(buffer), Count: node.count}
+			if len(h) < k {
+				heap.Push(&h, entry)
+			} else {
+				h[0] = entry
+				heap.Fix(&h, 0)
+			}
+			return
+		}
+		// Visiting the children in ascending order lets the smaller of
+		// equal prefixes in first, hence they are kept on ties.
+		for _, ch := range node.sortedChildKeys() {
+			buffer = append(buffer, byte(ch))
+			visit(node.children[ch])
+			buffer = buffer[:len(buffer)-1]
+		}
+	}
+	visit(t)
+
+	sort.Sort(sort.Reverse(h))
+	return h
+}
+
+// Returns the keys splitting the keys of the PrefixStore into n groups of
+// roughly equal size: the group i holds the keys from points[i-1], included,
+// to points[i], excluded, the first group starting at the smallest key and
+// the last one ending after the greatest. Fewer points are returned if there
+// are fewer than n keys.
+// Every point is found via Select, hence in time proportional to its length.
+func (t *PrefixStoreByteTrie) SplitPoints(n int) [][]byte {
+	var points [][]byte
+	for i := 1; i < n; i++ {
+		rank := i * t.count / n
+		if rank == 0 || len(points) > 0 && rank == (i-1)*t.count/n {
+			continue
+		}
+		key, _ := t.Select(rank)
+		points = append(points, key)
+	}
+	return points
+}
+
+// Returns the k prefixes of length depth having the most keys under them,
+// from the most to the least keys, and in ascending order of prefix among
+// equal counts. Keys shorter than depth have no such prefix and are not
+// counted.
+// The subtree counts kept by the nodes bound the count of every prefix
+// below a node, hence the subtrees that cannot make it to the top k are not
+// visited.
+func (t *PrefixStoreRuneTrie) TopPrefixes(depth, k int) []RunePrefixCount {
+	if depth <= 0 || k <= 0 {
+		return nil
+	}
+
+	h := make(runePrefixCountHeap, 0, k)
+	buffer := make([]rune, 0, depth)
+	var visit func(node *PrefixStoreRuneTrie)
+	visit = func(node *PrefixStoreRuneTrie) {
+		if len(h) == k && node.count <= h[0].Count {
+			// The prefixes below node do not have more keys than the
+			// least of the top k.
+			return
+		}
+		if len(buffer) == depth {
+			entry := RunePrefixCount{Prefix: slices.Clone(buffer), Count: node.count}
+			if len(h) < k {
+				heap.Push(&h, entry)
+			} else {
+				h[0] = entry
+				heap.Fix(&h, 0)
+			}
+			return
+		}
+		// Visiting the children in ascending order lets the smaller of
+		// equal prefixes in first, hence they are kept on ties.
+		for _, ch := range node.sortedChildKeys() {
+			buffer = append(buffer, ch)
+			visit(node.children[ch])
+			buffer = buffer[:len(buffer)-1]
+		}
+	}
+	visit(t)
+
+	sort.Sort(sort.Reverse(h))
+	return h
+}
+
+// Returns the keys splitting the keys of the PrefixStore into n groups of
+// roughly equal size: the group i holds the keys from points[i-1], included,
+// to points[i], excluded, the first group starting at the smallest key and
+// the last one ending after the greatest. Fewer points are returned if there
+// are fewer than n keys.
+// Every point is found via Select, hence in time proportional to its length.
+func (t *PrefixStoreRuneTrie) SplitPoints(n int) [][]rune {
+	var points [][]rune
+	for i := 1; i < n; i++ {
+		rank := i * t.count / n
+		if rank == 0 || len(points) > 0 && rank == (i-1)*t.count/n {
+			continue
+		}
+		key, _ := t.Select(rank)
+		points = append(points, key)
+	}
+	return points
+}
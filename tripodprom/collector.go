@@ -0,0 +1,88 @@
+// Package tripodprom exports the metrics of instrumented tripod stores to
+// Prometheus.
+package tripodprom
+
+import (
+	"github.com/arpitbbhayani/tripod"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	keysDesc = prometheus.NewDesc(
+		"tripod_store_keys",
+		"Number of keys present in the store.",
+		[]string{"store"}, nil)
+
+	durationDesc = prometheus.NewDesc(
+		"tripod_store_operation_duration_seconds",
+		"Latency of the operations on the store.",
+		[]string{"store", "operation"}, nil)
+
+	resultsDesc = prometheus.NewDesc(
+		"tripod_store_prefix_search_results",
+		"Number of keys returned by prefix searches on the store.",
+		[]string{"store"}, nil)
+)
+
+// Represents a prometheus.Collector of the metrics recorded by instrumented
+// stores, labelled by store name. The metrics are read when collected, hence
+// the instrumented stores do not depend on Prometheus.
+type Collector struct {
+	metrics map[string]*tripod.StoreMetrics
+}
+
+// Creates and returns reference to a new instance of Collector collecting the
+// metrics of the given stores, by name.
+//
+//	store := tripod.CreateInstrumentedBytePrefixStore(tr)
+//	prometheus.MustRegister(tripodprom.CreateCollector(map[string]*tripod.StoreMetrics{
+//		"words": store.Metrics(),
+//	}))
+func CreateCollector(metrics map[string]*tripod.StoreMetrics) *Collector {
+	c := &Collector{metrics: make(map[string]*tripod.StoreMetrics, len(metrics))}
+	for name, m := range metrics {
+		c.metrics[name] = m
+	}
+	return c
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- keysDesc
+	ch <- durationDesc
+	ch <- resultsDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	for name, m := range c.metrics {
+		snapshot := m.Snapshot()
+		ch <- prometheus.MustNewConstMetric(keysDesc, prometheus.GaugeValue, float64(snapshot.Keys), name)
+
+		operations := []struct {
+			name      string
+			histogram tripod.HistogramSnapshot
+		}{
+			{"put", snapshot.Put},
+			{"exists", snapshot.Exists},
+			{"prefix_search", snapshot.PrefixSearch},
+			{"delete", snapshot.Delete},
+		}
+		for _, op := range operations {
+			ch <- constHistogram(durationDesc, op.histogram, 1e-9, name, op.name)
+		}
+		ch <- constHistogram(resultsDesc, snapshot.PrefixSearchResults, 1, name)
+	}
+}
+
+// Returns the histogram as a Prometheus histogram, with cumulative buckets,
+// multiplying bounds and sum by scale.
+func constHistogram(desc *prometheus.Desc, h tripod.HistogramSnapshot, scale float64, labels ...string) prometheus.Metric {
+	buckets := make(map[float64]uint64, len(h.Bounds))
+	var cumulative uint64
+	for i, bound := range h.Bounds {
+		cumulative += h.Counts[i]
+		buckets[float64(bound)*scale] = cumulative
+	}
+	return prometheus.MustNewConstHistogram(desc, h.Count, float64(h.Sum)*scale, buckets, labels...)
+}
@@ -0,0 +1,284 @@
+package tripod
+
+import (
+	"container/heap"
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Number of expired keys reclaimed, at most, by every Put and Delete on a
+// TTLPrefixStore, so that expired keys are reclaimed even without a sweeper
+// while keeping the cost of every call bounded.
+const ttlSweepBatch = 8
+
+// Represents the source of the current time of a TTLPrefixStore, and of the
+// ticks of its sweeper, so that tests can control them.
+type Clock interface {
+	Now() time.Time
+
+	// Returns a channel delivering the time every d, d being positive, and
+	// the function stopping the deliveries.
+	NewTicker(d time.Duration) (ticks <-chan time.Time, stop func())
+}
+
+// The Clock reading the system time.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+func (systemClock) NewTicker(d time.Duration) (<-chan time.Time, func()) {
+	ticker := time.NewTicker(d)
+	return ticker.C, ticker.Stop
+}
+
+// Options of a TTLPrefixStore. The zero value never expires keys put with
+// Put, and reads the system time.
+type TTLOptions struct {
+	// Time to live of the keys put with Put; zero or less means they never
+	// expire.
+	TTL time.Duration
+
+	// Source of the current time, the system time if nil.
+	Clock Clock
+}
+
+// The expiry of a key, along with its index in the expiry heap.
+type ttlEntry struct {
+	key       string
+	expiresAt time.Time
+	index     int
+}
+
+// A min-heap of expiries, the earliest being at the top.
+type ttlHeap []*ttlEntry
+
+func (h ttlHeap) Len() int           { return len(h) }
+func (h ttlHeap) Less(i, j int) bool { return h[i].expiresAt.Before(h[j].expiresAt) }
+func (h ttlHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+func (h *ttlHeap) Push(x interface{}) {
+	entry := x.(*ttlEntry)
+	entry.index = len(*h)
+	*h = append(*h, entry)
+}
+func (h *ttlHeap) Pop() interface{} {
+	old := *h
+	entry := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return entry
+}
+
+// Represents a BytePrefixStore whose keys can expire. A key past its expiry
+// is gone from Exists, PrefixSearch and Count right away, and its node is
+// reclaimed later, a few keys at a time by every Put and Delete, by Sweep,
+// or by a background sweeper started with StartSweeper.
+// A TTLPrefixStore is safe for concurrent use.
+type TTLPrefixStore struct {
+	mu    sync.RWMutex
+	trie  *PrefixStoreByteTrie
+	ttl   time.Duration
+	clock Clock
+
+	// Expiry of every key that expires, by key and by time.
+	expiries map[string]*ttlEntry
+	queue    ttlHeap
+}
+
+// Creates and returns reference to a new instance of TTLPrefixStore.
+// maxKeySizeInBytes is the maximum size of the key ([]byte) that should be
+// allowed to be added to the store. When tried to put key of length more than
+// maxKeySizeInBytes, the method will return the error.
+func CreateTTLPrefixStore(maxKeySizeInBytes int, opts TTLOptions) *TTLPrefixStore {
+	clock := opts.Clock
+	if clock == nil {
+		clock = systemClock{}
+	}
+	return &TTLPrefixStore{
+		trie:     CreatePrefixStoreByteTrie(maxKeySizeInBytes),
+		ttl:      opts.TTL,
+		clock:    clock,
+		expiries: make(map[string]*ttlEntry),
+	}
+}
+
+// Adds the key to the store, expiring after the time to live of the store,
+// and returns if key was succesfully added and any error encountered. Putting
+// a key already present resets its expiry.
+// A non nil error is returned if len(key) > maxKeySizeInBytes
+func (s *TTLPrefixStore) Put(key []byte) (bool, error) {
+	return s.PutWithTTL(key, s.ttl)
+}
+
+// Adds the key to the store, expiring after ttl, zero or less meaning never,
+// and returns if key was succesfully added and any error encountered. Putting
+// a key already present resets its expiry. An expired key that is not yet
+// reclaimed is reported as added.
+// A non nil error is returned if len(key) > maxKeySizeInBytes
+func (s *TTLPrefixStore) PutWithTTL(key []byte, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+	s.sweep(now, ttlSweepBatch)
+
+	expired := s.expired(key, now)
+	newlyAdded, err := s.trie.Put(key)
+	if err != nil {
+		return false, err
+	}
+
+	entry := s.expiries[string(key)]
+	switch {
+	case ttl <= 0 && entry != nil:
+		heap.Remove(&s.queue, entry.index)
+		delete(s.expiries, entry.key)
+	case ttl > 0 && entry != nil:
+		entry.expiresAt = now.Add(ttl)
+		heap.Fix(&s.queue, entry.index)
+	case ttl > 0:
+		entry = &ttlEntry{key: string(key), expiresAt: now.Add(ttl)}
+		heap.Push(&s.queue, entry)
+		s.expiries[entry.key] = entry
+	}
+	return newlyAdded || expired, nil
+}
+
+// Checks and returns if given key is present in the store and not expired.
+func (s *TTLPrefixStore) Exists(key []byte) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.trie.Exists(key) && !s.expired(key, s.clock.Now())
+}
+
+// Returns the time at which key expires, and if the key is present in the
+// store and expires.
+func (s *TTLPrefixStore) ExpiresAt(key []byte) (time.Time, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entry := s.expiries[string(key)]
+	if entry == nil || !s.clock.Now().Before(entry.expiresAt) {
+		return time.Time{}, false
+	}
+	return entry.expiresAt, true
+}
+
+// Does the prefix search and returns a reference to list (*list.List)
+// containing the keys present in the store for the given prefix and not
+// expired. Each element of the list is []byte.
+func (s *TTLPrefixStore) PrefixSearch(prefix []byte) *list.List {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entries := s.trie.PrefixSearch(prefix)
+	if len(s.expiries) == 0 {
+		return entries
+	}
+	now := s.clock.Now()
+	for e := entries.Front(); e != nil; {
+		next := e.Next()
+		if s.expired(e.Value.([]byte), now) {
+			entries.Remove(e)
+		}
+		e = next
+	}
+	return entries
+}
+
+// Removes the key from the store and returns if the key was present and not
+// expired.
+func (s *TTLPrefixStore) Delete(key []byte) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+	s.sweep(now, ttlSweepBatch)
+
+	expired := s.expired(key, now)
+	if entry := s.expiries[string(key)]; entry != nil {
+		heap.Remove(&s.queue, entry.index)
+		delete(s.expiries, entry.key)
+	}
+	return s.trie.Delete(key) && !expired
+}
+
+// Returns the number of keys present in the store and not expired. The
+// expired keys are reclaimed first.
+func (s *TTLPrefixStore) Count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(s.clock.Now(), -1)
+	return s.trie.Count()
+}
+
+// Reclaims all the expired keys and returns their number.
+func (s *TTLPrefixStore) Sweep() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.sweep(s.clock.Now(), -1)
+}
+
+// Starts a goroutine calling Sweep on every tick of the clock, every interval,
+// and returns the function stopping it, which waits for the goroutine to
+// return. No goroutine is started if interval is zero or less, and the
+// returned function does nothing.
+func (s *TTLPrefixStore) StartSweeper(interval time.Duration) (stop func()) {
+	if interval <= 0 {
+		return func() {}
+	}
+
+	ticks, stopTicks := s.clock.NewTicker(interval)
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer stopTicks()
+		for {
+			select {
+			case <-ticks:
+				s.Sweep()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			wg.Wait()
+		})
+	}
+}
+
+// Checks and returns if key has expired at now. The caller must hold the
+// lock.
+func (s *TTLPrefixStore) expired(key []byte, now time.Time) bool {
+	entry := s.expiries[string(key)]
+	return entry != nil && !now.Before(entry.expiresAt)
+}
+
+// Removes the keys expired at now, at most limit of them unless limit is
+// negative, and returns their number. The caller must hold the lock.
+func (s *TTLPrefixStore) sweep(now time.Time, limit int) int {
+	reclaimed := 0
+	for len(s.queue) > 0 && reclaimed != limit && !now.Before(s.queue[0].expiresAt) {
+		entry := heap.Pop(&s.queue).(*ttlEntry)
+		delete(s.expiries, entry.key)
+		s.trie.Delete([]byte(entry.key))
+		reclaimed++
+	}
+	return reclaimed
+}
+
+var _ BytePrefixStore = (*TTLPrefixStore)(nil)
@@ -0,0 +1,81 @@
+package tripod
+
+// Represents what a Walk should do after visiting a node.
+type WalkAction int
+
+const (
+	// Goes on with the children of the node, then with its next sibling.
+	WalkContinue WalkAction = iota
+
+	// Goes on with the next sibling of the node, skipping its children.
+	WalkSkipChildren
+
+	// Ends the walk.
+	WalkStop
+)
+
+// Does a Depth First Search traversal of the nodes of the PrefixStore under
+// prefix, visiting a node before its children and the children in ascending
+// order, and calls fn with the path to every node and if the path is a key.
+// The node of prefix itself is visited first; an empty prefix visits the
+// root, with an empty path. The action returned by fn controls the traversal.
+// The path passed to fn is valid only for the duration of the call.
+func (t *PrefixStoreByteTrie) Walk(prefix []byte, fn func(path []byte, isKey bool) WalkAction) {
+	subTrie := t.get(prefix)
+	if subTrie == nil {
+		return
+	}
+	buffer := make([]byte, len(prefix), t.maxKeySizeInBytes+1)
+	copy(buffer, prefix)
+	subTrie.walk(buffer, fn)
+}
+
+// Visits t and, unless told otherwise, the nodes under it, and returns false
+// once the walk is to be stopped.
+func (t *PrefixStoreByteTrie) walk(buffer []byte, fn func(path []byte, isKey bool) WalkAction) bool {
+	switch fn(buffer, t.isLast) {
+	case WalkStop:
+		return false
+	case WalkSkipChildren:
+		return true
+	}
+	for _, ch := range t.sortedChildKeys() {
+		if !t.children[ch].walk(append(buffer, byte(ch)), fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// Does a Depth First Search traversal of the nodes of the PrefixStore under
+// prefix, visiting a node before its children and the children in ascending
+// order, and calls fn with the path to every node and if the path is a key.
+// The node of prefix itself is visited first; an empty prefix visits the
+// root, with an empty path. The action returned by fn controls the traversal.
+// The path passed to fn is valid only for the duration of the call.
+func (t *PrefixStoreRuneTrie) Walk(prefix []rune, fn func(path []rune, isKey bool) WalkAction) {
+	subTrie := t.get(prefix)
+	if subTrie == nil {
+		return
+	}
+	buffer := make([]rune, len(prefix), t.maxKeySizeInRunes+1)
+	copy(buffer, prefix)
+	subTrie.walk(buffer, fn)
+}
+
+// Visits t and, unless told otherwise, the nodes under it, and returns false
+// once the walk is to be stopped.
+func (t *PrefixStoreRuneTrie) walk(buffer []rune, fn func(path []rune, isKey bool) WalkAction) bool {
+	switch fn(buffer, t.isLast) {
+	case WalkStop:
+		return false
+	case WalkSkipChildren:
+		return true
+	}
+	for _, ch := range t.sortedChildKeys() {
+		if !t.children[ch].walk(append(buffer, ch), fn) {
+			return false
+		}
+	}
+	return true
+}